@@ -0,0 +1,392 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/spf13/viper"
+	"github.com/wildeyedskies/go-mpv/mpv"
+)
+
+// 动作所属的上下文，帮助界面按此分组
+const (
+	contextGlobal = "global"
+	contextTable  = "table"
+)
+
+// action 按键和命令行共用的操作，由主界面处理按键。Keys 可以通过配置 keys.<Name> 覆盖
+type action struct {
+	Name    string
+	Aliases []string
+	Keys    []string
+	Context string
+	Help    string
+	Usage   string // 命令行参数，如 "<query>"
+	PassKey bool   // 按键触发时将按键名作为参数传入
+	Run     func(arg string) error
+}
+
+// keyName 按键名：可打印字符为字符本身，空格为 Space，其余为 tcell 的键名（Right、PgDn、Ctrl-C 等）
+func keyName(event *tcell.EventKey) string {
+	if event.Key() == tcell.KeyRune {
+		if event.Rune() == ' ' {
+			return "Space"
+		}
+		return string(event.Rune())
+	}
+	if name, ok := tcell.KeyNames[event.Key()]; ok {
+		return name
+	}
+	return event.Name()
+}
+
+// actions 返回所有动作，首次调用时创建并应用配置中的按键
+func (a *Application) actions() []*action {
+	if a.actionList != nil {
+		return a.actionList
+	}
+
+	a.actionList = a.defaultActions()
+	for _, act := range a.actionList {
+		key := "keys." + act.Name
+		if viper.IsSet(key) {
+			if keys := viper.GetStringSlice(key); len(keys) > 0 {
+				act.Keys = keys
+			}
+		}
+	}
+	return a.actionList
+}
+
+func (a *Application) findAction(name string) (*action, bool) {
+	for _, act := range a.actions() {
+		if act.Name == name {
+			return act, true
+		}
+		for _, alias := range act.Aliases {
+			if alias == name {
+				return act, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// actionForKey 查找主界面按键对应的动作
+func (a *Application) actionForKey(event *tcell.EventKey) (*action, string, bool) {
+	name := keyName(event)
+	for _, act := range a.actions() {
+		for _, key := range act.Keys {
+			if key != name {
+				continue
+			}
+			if act.PassKey {
+				return act, name, true
+			}
+			return act, "", true
+		}
+	}
+	return nil, "", false
+}
+
+// runAction 执行动作，出错时显示在状态行
+func (a *Application) runAction(act *action, arg string) {
+	if err := act.Run(arg); err != nil {
+		a.setMessage("[red]" + act.Name + ": " + err.Error())
+	}
+}
+
+// runCommand 执行命令行输入，如 "vol 40"
+func (a *Application) runCommand(line string) error {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return nil
+	}
+	name, arg, _ := strings.Cut(line, " ")
+	act, ok := a.findAction(name)
+	if !ok {
+		return fmt.Errorf("unknown command: %s", name)
+	}
+	return act.Run(strings.TrimSpace(arg))
+}
+
+// do 将无参数的操作包装为 Run
+func do(f func()) func(string) error {
+	return func(string) error {
+		f()
+		return nil
+	}
+}
+
+func (a *Application) defaultActions() []*action {
+	return []*action{
+		// 播放控制
+		{Name: "pause", Keys: []string{"Space"}, Context: contextGlobal, Help: "Play / pause", Run: do(a.togglePause)},
+		{Name: "next", Keys: []string{"n", "N", "Right"}, Context: contextGlobal, Help: "Next track", Run: do(a.playNextSong)},
+		{Name: "prev", Keys: []string{"p", "P", "Left"}, Context: contextGlobal, Help: "Previous track", Run: do(a.playPreviousSong)},
+		{Name: "volup", Keys: []string{"+", "="}, Context: contextGlobal, Help: "Volume up", Run: do(func() { a.SetVolume(true) })},
+		{Name: "voldown", Keys: []string{"-", "_"}, Context: contextGlobal, Help: "Volume down", Run: do(func() { a.SetVolume(false) })},
+		{Name: "volume", Aliases: []string{"vol"}, Context: contextGlobal, Help: "Set volume (40, +5, -5)", Usage: "<level>", Run: a.volumeCommand},
+		{Name: "mute", Keys: []string{"m", "M"}, Context: contextGlobal, Help: "Mute / unmute", Run: do(a.muteButton)},
+		{Name: "seek", Context: contextGlobal, Help: "Seek to a position (2:30, +10, -10)", Usage: "<position>", Run: a.seekCommand},
+		{Name: "slower", Keys: []string{"["}, Context: contextGlobal, Help: "Slow down playback", Run: func(string) error { return a.stepSpeed(-speedStep) }},
+		{Name: "faster", Keys: []string{"]"}, Context: contextGlobal, Help: "Speed up playback", Run: func(string) error { return a.stepSpeed(speedStep) }},
+		{Name: "speed", Keys: []string{"\\"}, Context: contextGlobal, Help: "Set playback speed (1.5, +0.1), or reset to the default for music / podcasts", Usage: "[speed|reset]", Run: a.speedCommand},
+		{Name: "eq", Aliases: []string{"equalizer"}, Keys: []string{"e"}, Context: contextGlobal, Help: "Equalizer and audio effects", Usage: "[preset|night|mono]", Run: a.eqCommand},
+		{Name: "replaygain", Aliases: []string{"rg"}, Keys: []string{"G"}, Context: contextGlobal, Help: "Cycle ReplayGain off / track / album", Usage: "[off|track|album|preamp <dB>|clip on|loudnorm on]", Run: a.replayGainCommand},
+		{Name: "device", Keys: []string{"d"}, Context: contextGlobal, Help: "Audio output device", Usage: "[name]", Run: a.deviceCommand},
+		{Name: "loop", Keys: []string{"l"}, Context: contextGlobal, Help: "A-B loop: set A, set B, then clear", Usage: `[a|b [position]|off|save|load|delete "<name>"]`, Run: a.loopCommand},
+		{Name: "loops", Keys: []string{"L"}, Context: contextGlobal, Help: "Saved loops of the current track", Run: do(a.showLoops)},
+		{Name: "crossfade", Context: contextGlobal, Help: "Crossfade between tracks (0-12 seconds, off)", Usage: "<seconds|off>", Run: a.crossfadeCommand},
+		{Name: "sleep", Keys: []string{"z"}, Context: contextGlobal, Help: "Sleep timer: cycle 15 / 30 / 60 min, after track, after album, off", Usage: "<minutes|track|album|off>", Run: a.sleepCommand},
+		{Name: "alarm", Context: contextGlobal, Help: "Wake-up alarm playing a playlist with a fade-in", Usage: `<HH:MM> "<playlist>"|off`, Run: a.alarmCommand},
+		{Name: "queue", Keys: []string{"Q"}, Context: contextGlobal, Help: "Play queue (clear, add, next)", Usage: "[clear|add|next]", Run: a.queueCommand},
+
+		// 视图
+		{Name: "help", Aliases: []string{"h"}, Keys: []string{"?"}, Context: contextGlobal, Help: "Key bindings and commands", Run: do(a.showHelp)},
+		{Name: "command", Keys: []string{":"}, Context: contextGlobal, Help: "Command line", Run: do(a.showCommandLine)},
+		{Name: "lyrics", Keys: []string{"y"}, Context: contextGlobal, Help: "Lyrics of the current track", Run: do(a.showLyrics)},
+		{Name: "stats", Keys: []string{"s"}, Context: contextGlobal, Help: "Listening stats", Run: do(a.showStats)},
+		{Name: "mix", Keys: []string{"x"}, Context: contextGlobal, Help: "Build a random mix by genre / year range", Run: do(a.showMixBuilder)},
+		{Name: "reload", Keys: []string{"q"}, Context: contextGlobal, Help: "Re-roll the current mix", Run: func(string) error {
+			go func() {
+				if err := a.loadMusic(); err != nil {
+					a.setMessage("[red]load music failed: " + err.Error())
+				}
+			}()
+			return nil
+		}},
+		{Name: "folders", Keys: []string{"f"}, Context: contextGlobal, Help: "Select music folder (library)", Run: do(a.showMusicFolders)},
+		{Name: "albums", Keys: []string{"a"}, Context: contextGlobal, Help: "Album lists", Run: do(a.showAlbums)},
+		{Name: "stations", Keys: []string{"i"}, Context: contextGlobal, Help: "Internet radio stations", Run: do(a.showStations)},
+		{Name: "podcasts", Keys: []string{"c"}, Context: contextGlobal, Help: "Podcasts", Run: do(a.showPodcasts)},
+		{Name: "bookmarks", Keys: []string{"b"}, Context: contextGlobal, Help: "Bookmarks", Run: do(a.showBookmarks)},
+		{Name: "serverinfo", Keys: []string{"I"}, Context: contextGlobal, Help: "Server info", Run: do(a.showServerInfo)},
+		{Name: "server", Context: contextGlobal, Help: "Switch to another server profile", Usage: "<name>", Run: a.serverCommand},
+		{Name: "theme", Context: contextGlobal, Help: "Switch color theme", Usage: "<name>", Run: a.themeCommand},
+		{Name: "radiomode", Keys: []string{"R"}, Context: contextGlobal, Help: "Toggle radio mode", Run: do(a.toggleRadio)},
+		{Name: "quit", Aliases: []string{"exit"}, Keys: []string{"Esc", "Ctrl-C"}, Context: contextGlobal, Help: "Clear the filter, or quit", PassKey: true, Run: a.quitCommand},
+
+		// 歌曲列表
+		{Name: "search", Aliases: []string{"filter"}, Keys: []string{"/"}, Context: contextTable, Help: "Filter the current list as you type", Usage: "[query]", Run: a.filterCommand},
+		{Name: "find", Keys: []string{"S"}, Context: contextTable, Help: "Search the whole library on the server", Usage: "[query]", Run: a.findCommand},
+		{Name: "play", Context: contextTable, Help: "Search the library and play the results (artist:Radiohead)", Usage: "<query>", Run: a.playCommand},
+		{Name: "radio", Keys: []string{"r"}, Context: contextTable, Help: "Start radio from the selected track", Run: do(func() {
+			if song, ok := a.selectedSong(); ok {
+				go a.startRadio(song)
+			}
+		})},
+		{Name: "mark", Keys: []string{"v"}, Context: contextTable, Help: "Mark / unmark the selected track (Space: pause)", Run: do(a.toggleMark)},
+		{Name: "markrange", Keys: []string{"V"}, Context: contextTable, Help: "Mark everything since the last mark", Run: do(a.markRange)},
+		{Name: "unmark", Keys: []string{"u"}, Context: contextTable, Help: "Clear marks", Run: do(a.clearMarks)},
+		{Name: "actions", Keys: []string{"A"}, Context: contextTable, Help: "Actions on marked tracks", Run: do(a.showActions)},
+		{Name: "playlist", Context: contextTable, Help: "Add marked tracks to a playlist (created if missing)", Usage: "add <name>", Run: a.playlistCommand},
+		{Name: "columns", Keys: []string{"o"}, Context: contextTable, Help: "Choose columns and sort", Run: do(a.showColumns)},
+		{Name: "sort", Keys: []string{"1", "2", "3", "4", "5", "6", "7", "8", "9"}, Context: contextTable, Help: "Sort by the Nth column or a column name (+name adds a key)", Usage: "<column>", PassKey: true, Run: a.sortCommand},
+		{Name: "pagedown", Keys: []string{"PgDn"}, Context: contextTable, Help: "Next page", Run: do(a.nextPage)},
+		{Name: "pageup", Keys: []string{"PgUp"}, Context: contextTable, Help: "Previous page", Run: do(a.previousPage)},
+		{Name: "first", Keys: []string{"Home"}, Context: contextTable, Help: "First page", Run: do(func() { a.setPage(1) })},
+		{Name: "last", Keys: []string{"End"}, Context: contextTable, Help: "Last page", Run: do(func() { a.setPage(a.totalPages) })},
+	}
+}
+
+func (a *Application) quitCommand(key string) error {
+	// 有过滤条件时 Esc 先清除过滤而不是退出程序
+	if key == "Esc" && a.filter != "" {
+		a.clearFilter()
+		return nil
+	}
+	log.Println("user request exit program")
+
+	if a.mpvInstance != nil && a.mpvInstance.Mpv != nil {
+		a.mpvInstance.Command([]string{"quit"})
+	}
+
+	// main 在 Run 返回后保存书签和播放历史，等待写入完成再退出
+	a.application.Stop()
+	return nil
+}
+
+func (a *Application) volumeCommand(arg string) error {
+	if a.mpvInstance == nil || a.mpvInstance.Mpv == nil {
+		return errors.New("player is not available")
+	}
+	level, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return fmt.Errorf("invalid volume: %q", arg)
+	}
+	if strings.HasPrefix(arg, "+") || strings.HasPrefix(arg, "-") {
+		current, err := a.mpvInstance.GetProperty("volume", mpv.FORMAT_DOUBLE)
+		if err != nil {
+			return err
+		}
+		level += current.(float64)
+	}
+	return a.mpvInstance.SetProperty("volume", mpv.FORMAT_DOUBLE, max(min(level, 100), 0))
+}
+
+// parsePosition 解析 2:30、1:02:03 或秒数
+func parsePosition(text string) (float64, error) {
+	var seconds float64
+	for _, part := range strings.Split(text, ":") {
+		n, err := strconv.ParseFloat(part, 64)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid position: %q", text)
+		}
+		seconds = seconds*60 + n
+	}
+	return seconds, nil
+}
+
+func (a *Application) seekCommand(arg string) error {
+	if a.mpvInstance == nil || a.mpvInstance.Mpv == nil || a.currentSong == nil {
+		return errors.New("nothing is playing")
+	}
+
+	relative := strings.HasPrefix(arg, "+") || strings.HasPrefix(arg, "-")
+	seconds, err := parsePosition(strings.TrimLeft(arg, "+-"))
+	if err != nil {
+		return err
+	}
+	if relative {
+		if strings.HasPrefix(arg, "-") {
+			seconds = -seconds
+		}
+		return a.mpvInstance.Command([]string{"seek", strconv.FormatFloat(seconds, 'f', 1, 64), "relative"})
+	}
+
+	// 服务器端定位的流从 playOffset 开始
+	a.loadingMux.Lock()
+	offset := a.playOffset
+	a.loadingMux.Unlock()
+	return a.mpvInstance.Command([]string{"seek", strconv.FormatFloat(max(seconds-offset, 0), 'f', 1, 64), "absolute"})
+}
+
+func (a *Application) queueCommand(arg string) error {
+	switch arg {
+	case "":
+		a.showQueue()
+	case "clear":
+		a.clearQueue()
+		a.setMessage("[lightgreen]queue cleared")
+	case "add":
+		songs := a.targetSongs()
+		a.enqueue(songs)
+		a.setMessage(fmt.Sprintf("[lightgreen]%d songs enqueued", len(songs)))
+	case "next":
+		songs := a.targetSongs()
+		a.playNext(songs)
+		a.setMessage(fmt.Sprintf("[lightgreen]%d songs will play next", len(songs)))
+	default:
+		return fmt.Errorf("unknown queue command: %s", arg)
+	}
+	return nil
+}
+
+func (a *Application) filterCommand(arg string) error {
+	if arg == "" {
+		a.showFilter()
+		return nil
+	}
+	a.filterRestore = ""
+	a.setFilter(arg)
+	return nil
+}
+
+func (a *Application) findCommand(arg string) error {
+	if arg == "" {
+		a.serverSearch()
+		return nil
+	}
+	go func() {
+		if err := a.loadSource("search", a.searchSource(arg)); err != nil {
+			a.setMessage("[red]search failed: " + err.Error())
+		}
+	}()
+	return nil
+}
+
+// playCommand 用查询中的文字在服务器端搜索，再按字段前缀过滤并从第一首开始播放
+func (a *Application) playCommand(arg string) error {
+	f := parseFilter(arg)
+	if f.empty() {
+		return errors.New("usage: play <query>")
+	}
+	terms := append([]string{}, f.terms...)
+	for _, field := range f.fields {
+		terms = append(terms, field.value)
+	}
+	query := strings.Join(terms, " ")
+
+	go func() {
+		if err := a.loadSource("search", a.searchSource(query)); err != nil {
+			a.setMessage("[red]search failed: " + err.Error())
+			return
+		}
+		a.application.QueueUpdateDraw(func() {
+			a.filterRestore = ""
+			a.setFilter(arg)
+			if len(a.totalSongs) == 0 {
+				a.setMessage("[yellow]no songs match " + arg)
+				return
+			}
+			go a.playSongAtIndex(0)
+		})
+	}()
+	return nil
+}
+
+func (a *Application) playlistCommand(arg string) error {
+	sub, name, _ := strings.Cut(arg, " ")
+	name = strings.Trim(strings.TrimSpace(name), `"`)
+	if sub != "add" || name == "" {
+		return errors.New(`usage: playlist add "<name>"`)
+	}
+	songs := a.targetSongs()
+	if len(songs) == 0 {
+		return errors.New("no songs selected")
+	}
+
+	go func() {
+		playlists, err := a.subsonicClient.ListPlaylistsContext(a.ctx)
+		if err != nil {
+			a.setMessage("[red]load playlists failed: " + err.Error())
+			return
+		}
+		for _, playlist := range playlists {
+			if strings.EqualFold(playlist.Name, name) {
+				if err := a.subsonicClient.AddToPlaylistContext(a.ctx, playlist.ID, songIDs(songs)); err != nil {
+					a.setMessage("[red]add to playlist failed: " + err.Error())
+					return
+				}
+				a.setMessage(fmt.Sprintf("[lightgreen]%d songs added to %s", len(songs), name))
+				return
+			}
+		}
+		if err := a.subsonicClient.CreatePlaylistContext(a.ctx, name, songIDs(songs)); err != nil {
+			a.setMessage("[red]create playlist failed: " + err.Error())
+			return
+		}
+		a.setMessage(fmt.Sprintf("[lightgreen]playlist %s created with %d songs", name, len(songs)))
+	}()
+	return nil
+}
+
+// sortCommand 按键传入列序号，命令行可以使用列序号或列名，前缀 + 表示追加排序
+func (a *Application) sortCommand(arg string) error {
+	add := strings.HasPrefix(arg, "+")
+	arg = strings.TrimPrefix(arg, "+")
+	if n, err := strconv.Atoi(arg); err == nil {
+		a.sortColumn(n, add)
+		return nil
+	}
+	if _, ok := columnByKey(arg); !ok {
+		return fmt.Errorf("unknown column: %s", arg)
+	}
+	a.sortBy(arg, add)
+	return nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/spf13/viper"
+	"github.com/yhkl-dev/NaviCLI/subsonic"
+)
+
+// 播放位置少于该秒数时不保存书签
+const bookmarkMinPosition = 10
+
+// shouldBookmark 判断歌曲是否需要记录续播位置：播客、有声书，
+// 以及时长超过 bookmark.min_duration 秒的长曲目（如 DJ mix）
+func (a *Application) shouldBookmark(song subsonic.Song) bool {
+	if song.Type == "podcast" || song.Type == "audiobook" {
+		return true
+	}
+	minDuration := viper.GetInt("bookmark.min_duration")
+	return minDuration > 0 && song.Duration >= minDuration
+}
+
+// resumePosition 返回歌曲的书签位置（秒），没有书签时返回 0
+func (a *Application) resumePosition(ctx context.Context, song subsonic.Song) int {
+	if !a.shouldBookmark(song) {
+		return 0
+	}
+
+	bookmarks, err := a.subsonicClient.GetBookmarksContext(ctx)
+	if err != nil {
+		log.Println("load bookmarks failed:", err)
+		return 0
+	}
+	for _, bookmark := range bookmarks {
+		if bookmark.Entry.ID == song.ID {
+			return int(bookmark.Position / 1000)
+		}
+	}
+	return 0
+}
+
+// updateBookmark 中途停止时保存播放位置，播放完毕后删除书签
+func (a *Application) updateBookmark(client *subsonic.Client, song subsonic.Song, position int, completed bool) {
+	if !a.shouldBookmark(song) {
+		return
+	}
+
+	// 退出程序时 a.ctx 已取消，书签仍需保存
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(a.ctx), 5*time.Second)
+	defer cancel()
+
+	if completed {
+		// 没有书签时服务器会返回 not found，忽略即可
+		if err := client.DeleteBookmarkContext(ctx, song.ID); err != nil && !errors.Is(err, subsonic.ErrNotFound) {
+			log.Println("delete bookmark failed:", err)
+		}
+		return
+	}
+	if position < bookmarkMinPosition {
+		return
+	}
+	if err := client.CreateBookmarkContext(ctx, song.ID, int64(position)*1000, ""); err != nil {
+		log.Println("save bookmark failed:", err)
+	}
+}
+
+// showBookmarks 打开书签列表：Enter 从书签位置播放，x 删除
+func (a *Application) showBookmarks() {
+	go func() {
+		bookmarks, err := a.subsonicClient.GetBookmarksContext(a.ctx)
+		if err != nil {
+			a.application.QueueUpdateDraw(func() {
+				a.statusBar.SetText("[red]load bookmarks failed: " + err.Error())
+			})
+			return
+		}
+		a.application.QueueUpdateDraw(func() {
+			a.bookmarksUI(bookmarks)
+		})
+	}()
+}
+
+func (a *Application) bookmarksUI(bookmarks []subsonic.Bookmark) {
+	list := tview.NewList()
+	list.SetBorder(true).SetTitle(" Bookmarks (Enter resume, x delete) ")
+	for _, bookmark := range bookmarks {
+		entry := bookmark.Entry
+		secondary := fmt.Sprintf("[darkgray]%s / %s  %s",
+			formatDuration(int(bookmark.Position/1000)),
+			formatDuration(entry.Duration),
+			bookmark.Changed.Local().Format(time.DateTime))
+		if bookmark.Comment != "" {
+			secondary += "  " + tview.Escape(bookmark.Comment)
+		}
+		list.AddItem(fmt.Sprintf("%s [gray]- %s", tview.Escape(entry.Title), tview.Escape(entry.Artist)), secondary, 0, nil)
+	}
+	if len(bookmarks) == 0 {
+		list.AddItem("[darkgray]No bookmarks", "", 0, nil)
+	}
+
+	list.SetSelectedFunc(func(index int, _, _ string, _ rune) {
+		if index >= len(bookmarks) {
+			return
+		}
+		a.closeOverlay()
+		a.stopRadio()
+		go func() {
+			a.replaceSongs("bookmark", []subsonic.Song{bookmarks[index].Entry})
+			a.playSongAtIndex(0)
+		}()
+	})
+
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		index := list.GetCurrentItem()
+		if event.Rune() == 'x' && index < len(bookmarks) {
+			go func() {
+				if err := a.subsonicClient.DeleteBookmarkContext(a.ctx, bookmarks[index].Entry.ID); err != nil {
+					a.application.QueueUpdateDraw(func() {
+						a.closeOverlay()
+						a.statusBar.SetText("[red]delete bookmark failed: " + err.Error())
+					})
+					return
+				}
+				a.showBookmarks()
+			}()
+			return nil
+		}
+		return event
+	})
+
+	a.showOverlay("bookmarks", list, 80, 24)
+}
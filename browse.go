@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/yhkl-dev/NaviCLI/subsonic"
+)
+
+const albumBatchSize = 100
+
+// albumListTypes 专辑列表的排序方式，t 键切换
+var albumListTypes = []string{"newest", "recent", "frequent", "random", "alphabeticalByName", "alphabeticalByArtist"}
+
+// searchSource 使用 search3 分页搜索
+func (a *Application) searchSource(query string) songSource {
+	return func(ctx context.Context, offset, size int) ([]subsonic.Song, error) {
+		return a.subsonicClient.SearchSongsPageContext(ctx, query, size, offset)
+	}
+}
+
+// serverSearch 在服务器端搜索整个音乐库，结果分页加载
+func (a *Application) serverSearch() {
+	input := tview.NewInputField().
+		SetLabel("Search library: ").
+		SetFieldWidth(40)
+	input.SetBorder(true)
+
+	input.SetDoneFunc(func(key tcell.Key) {
+		query := input.GetText()
+		if key != tcell.KeyEnter || query == "" {
+			return
+		}
+		a.closeOverlay()
+		go func() {
+			if err := a.loadSource("search", a.searchSource(query)); err != nil {
+				a.application.QueueUpdateDraw(func() {
+					a.statusBar.SetText("[red]search failed: " + err.Error())
+				})
+			}
+		}()
+	})
+
+	a.showOverlay("server-search", input, 62, 3)
+}
+
+// showAlbums 打开专辑列表：Enter 加载专辑歌曲，t 切换排序，滚动到底部时继续加载
+func (a *Application) showAlbums() {
+	a.albumsUI(0)
+}
+
+func (a *Application) albumsUI(typeIndex int) {
+	listType := albumListTypes[typeIndex]
+	list := tview.NewList()
+	list.SetBorder(true).SetTitle(fmt.Sprintf(" Albums: %s (Enter open, t sort) ", listType))
+
+	var albums []subsonic.Album
+	fetching, done := false, false
+
+	// fetch 追加下一批专辑，在 UI 线程调用
+	fetch := func() {
+		if fetching || done {
+			return
+		}
+		fetching = true
+		offset := len(albums)
+		go func() {
+			batch, err := a.subsonicClient.GetAlbumList2Context(a.ctx, listType, albumBatchSize, offset)
+			a.application.QueueUpdateDraw(func() {
+				fetching = false
+				if err != nil {
+					a.closeOverlay()
+					a.statusBar.SetText("[red]load albums failed: " + err.Error())
+					return
+				}
+				done = len(batch) < albumBatchSize
+				albums = append(albums, batch...)
+				for _, album := range batch {
+					secondary := fmt.Sprintf("%d songs  %s", album.SongCount, formatDuration(album.Duration))
+					if album.Year > 0 {
+						secondary = fmt.Sprintf("%d  %s", album.Year, secondary)
+					}
+					list.AddItem(fmt.Sprintf("%s [gray]- %s", tview.Escape(album.Name), tview.Escape(album.Artist)), "[darkgray]"+secondary, 0, nil)
+				}
+				if len(albums) == 0 {
+					list.AddItem("[darkgray]No albums", "", 0, nil)
+				}
+			})
+		}()
+	}
+
+	list.SetChangedFunc(func(index int, _, _ string, _ rune) {
+		// 接近末尾时加载下一批
+		if index >= len(albums)-10 {
+			fetch()
+		}
+	})
+
+	list.SetSelectedFunc(func(index int, _, _ string, _ rune) {
+		if index >= len(albums) {
+			return
+		}
+		album := albums[index]
+		a.closeOverlay()
+		go func() {
+			ctx := a.beginRefresh()
+			detail, err := a.subsonicClient.GetAlbumContext(ctx, album.ID)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				a.application.QueueUpdateDraw(func() {
+					a.statusBar.SetText("[red]load album failed: " + err.Error())
+				})
+				return
+			}
+			a.stopRadio()
+			a.replaceSongs("album", detail.Songs)
+		}()
+	})
+
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Rune() == 't' {
+			a.albumsUI((typeIndex + 1) % len(albumListTypes))
+			return nil
+		}
+		return event
+	})
+
+	a.showOverlay("albums", list, 80, 24)
+	fetch()
+}
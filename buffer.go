@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/yhkl-dev/NaviCLI/streamproxy"
+	"github.com/yhkl-dev/NaviCLI/subsonic"
+)
+
+const bufferBarWidth = 30
+
+// bufferBar 根据代理的预读进度生成缓冲条，没有代理时返回空字符串
+func (a *Application) bufferBar(songID string) string {
+	if a.streamProxy == nil {
+		return ""
+	}
+	health, ok := a.streamProxy.Health(songID)
+	if !ok {
+		return "[darkgray]" + strings.Repeat("░", bufferBarWidth) + " Connecting..."
+	}
+
+	switch {
+	case health.Err != nil:
+		return "[red]" + strings.Repeat("░", bufferBarWidth) + " Buffer failed"
+	case health.Cached:
+		return "[lightgreen]" + strings.Repeat("▓", bufferBarWidth) + "[darkgray] cached"
+	}
+
+	progress := health.Progress()
+	if progress < 0 {
+		// 长度未知（转码流），只显示已下载的大小
+		return fmt.Sprintf("[yellow]%s[darkgray] %.1f MB buffered",
+			strings.Repeat("▓", bufferBarWidth), float64(health.Downloaded)/1024/1024)
+	}
+
+	filled := int(progress * bufferBarWidth)
+	return fmt.Sprintf("[yellow]%s[darkgray]%s %.0f%% buffered",
+		strings.Repeat("▓", filled), strings.Repeat("░", bufferBarWidth-filled), progress*100)
+}
+
+// loadingInfo 加载中的歌曲信息，显示真实的缓冲进度
+func (a *Application) loadingInfo(index int, song subsonic.Song) string {
+	bar := a.bufferBar(song.ID)
+	if bar == "" {
+		bar = "[yellow]Loading..."
+	}
+	return fmt.Sprintf(`
+[-]Current %d:
+[yellow]%s [darkgray](Loading...)
+
+[darkgray][play] %s
+[darkgray][source] %.1f MB
+[darkgray][favourite]
+
+[gray]%s - %s
+[gray]%s
+%s`,
+		index+1,
+		song.Title,
+		formatDuration(song.Duration),
+		float64(song.Size)/1024/1024,
+		song.Artist,
+		song.Album,
+		song.Album,
+		bar)
+}
+
+// openCache 按配置打开离线缓存，未启用或失败时返回 nil。
+// 不同服务器的歌曲 ID 可能重复，非默认服务器使用单独的子目录
+func openCache(server string) *streamproxy.Cache {
+	if !viper.GetBool("cache.enabled") {
+		return nil
+	}
+
+	dir := viper.GetString("cache.dir")
+	if dir == "" {
+		base, err := os.UserCacheDir()
+		if err != nil {
+			base = "."
+		}
+		dir = filepath.Join(base, "navicli", "songs")
+	}
+	if server != "" && server != "default" {
+		dir = filepath.Join(dir, server)
+	}
+
+	cache, err := streamproxy.NewCache(dir, viper.GetInt64("cache.max_size_mb")*1024*1024)
+	if err != nil {
+		log.Println("open cache failed:", err)
+		return nil
+	}
+	return cache
+}
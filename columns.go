@@ -0,0 +1,368 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/spf13/viper"
+	"github.com/yhkl-dev/NaviCLI/subsonic"
+)
+
+// songColumn 歌曲表格中可选的一列
+type songColumn struct {
+	Key      string
+	Title    string
+	Align    int
+	MaxWidth int
+	Expand   int
+	Color    tcell.Color
+	Text     func(subsonic.Song) string
+	Compare  func(x, y subsonic.Song) int
+}
+
+func compareText(x, y string) int {
+	return strings.Compare(strings.ToLower(x), strings.ToLower(y))
+}
+
+func numberText(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+var songColumns = []songColumn{
+	{Key: "track", Title: "Track", Align: tview.AlignRight, Color: tcell.ColorGray,
+		Text:    func(s subsonic.Song) string { return numberText(s.Track) },
+		Compare: func(x, y subsonic.Song) int { return cmp.Compare(x.Track, y.Track) }},
+	{Key: "title", Title: "Title", Expand: 1, Color: tcell.ColorWhite,
+		Text:    func(s subsonic.Song) string { return s.Title },
+		Compare: func(x, y subsonic.Song) int { return compareText(x.Title, y.Title) }},
+	{Key: "artist", Title: "Artist", MaxWidth: 25, Color: tcell.ColorGray,
+		Text:    func(s subsonic.Song) string { return s.Artist },
+		Compare: func(x, y subsonic.Song) int { return compareText(x.Artist, y.Artist) }},
+	{Key: "album", Title: "Album", MaxWidth: 25, Color: tcell.ColorGray,
+		Text:    func(s subsonic.Song) string { return s.Album },
+		Compare: func(x, y subsonic.Song) int { return compareText(x.Album, y.Album) }},
+	{Key: "year", Title: "Year", Align: tview.AlignRight, Color: tcell.ColorGray,
+		Text:    func(s subsonic.Song) string { return numberText(s.Year) },
+		Compare: func(x, y subsonic.Song) int { return cmp.Compare(x.Year, y.Year) }},
+	{Key: "genre", Title: "Genre", MaxWidth: 15, Color: tcell.ColorGray,
+		Text:    func(s subsonic.Song) string { return s.Genre },
+		Compare: func(x, y subsonic.Song) int { return compareText(x.Genre, y.Genre) }},
+	{Key: "duration", Title: "Time", Align: tview.AlignRight, Color: tcell.ColorGray,
+		Text:    func(s subsonic.Song) string { return formatDuration(s.Duration) },
+		Compare: func(x, y subsonic.Song) int { return cmp.Compare(x.Duration, y.Duration) }},
+	{Key: "bitrate", Title: "kbps", Align: tview.AlignRight, Color: tcell.ColorGray,
+		Text:    func(s subsonic.Song) string { return numberText(s.BitRate) },
+		Compare: func(x, y subsonic.Song) int { return cmp.Compare(x.BitRate, y.BitRate) }},
+	{Key: "suffix", Title: "Format", Color: tcell.ColorGray,
+		Text:    func(s subsonic.Song) string { return s.Suffix },
+		Compare: func(x, y subsonic.Song) int { return compareText(x.Suffix, y.Suffix) }},
+	{Key: "playcount", Title: "Plays", Align: tview.AlignRight, Color: tcell.ColorGray,
+		Text:    func(s subsonic.Song) string { return numberText(s.PlayCount) },
+		Compare: func(x, y subsonic.Song) int { return cmp.Compare(x.PlayCount, y.PlayCount) }},
+	{Key: "rating", Title: "Rating", Color: tcell.ColorYellow,
+		Text:    func(s subsonic.Song) string { return strings.Repeat("★", max(min(s.UserRating, 5), 0)) },
+		Compare: func(x, y subsonic.Song) int { return cmp.Compare(x.UserRating, y.UserRating) }},
+	{Key: "starred", Title: "♥", Color: tcell.ColorRed,
+		Text: func(s subsonic.Song) string {
+			if s.Starred.IsZero() {
+				return ""
+			}
+			return "♥"
+		},
+		Compare: func(x, y subsonic.Song) int { return x.Starred.Compare(y.Starred) }},
+}
+
+var defaultColumns = []string{"title", "artist", "album", "duration"}
+
+// fixedColumns 表格最左侧固定的标记列和序号列
+const fixedColumns = 2
+
+func columnByKey(key string) (songColumn, bool) {
+	for _, col := range songColumns {
+		if col.Key == key {
+			return col, true
+		}
+	}
+	return songColumn{}, false
+}
+
+// sortKey 排序字段，配置中写作 "year" 或 "-year"（降序）
+type sortKey struct {
+	Column string
+	Desc   bool
+}
+
+func (k sortKey) String() string {
+	if k.Desc {
+		return "-" + k.Column
+	}
+	return k.Column
+}
+
+// tableLayout 每个视图的列和排序，保存在配置的 columns.<view> 中
+type tableLayout struct {
+	Columns []string
+	Sort    []sortKey
+}
+
+func (a *Application) layout() *tableLayout {
+	if a.layouts == nil {
+		a.layouts = make(map[string]*tableLayout)
+	}
+	if l, ok := a.layouts[a.view]; ok {
+		return l
+	}
+
+	l := &tableLayout{Columns: defaultColumns}
+	prefix := "columns." + a.view
+	if fields := viper.GetStringSlice(prefix + ".fields"); len(fields) > 0 {
+		l.Columns = nil
+		for _, field := range fields {
+			if _, ok := columnByKey(field); ok {
+				l.Columns = append(l.Columns, field)
+			}
+		}
+	}
+	for _, field := range viper.GetStringSlice(prefix + ".sort") {
+		key := sortKey{Column: strings.TrimPrefix(field, "-"), Desc: strings.HasPrefix(field, "-")}
+		if _, ok := columnByKey(key.Column); ok {
+			l.Sort = append(l.Sort, key)
+		}
+	}
+	a.layouts[a.view] = l
+	return l
+}
+
+// saveLayout 将当前视图的列和排序写回配置文件
+func (a *Application) saveLayout() {
+	l := a.layout()
+	sort := make([]string, len(l.Sort))
+	for i, key := range l.Sort {
+		sort[i] = key.String()
+	}
+	prefix := "columns." + a.view
+	err := saveSettings(map[string]any{
+		prefix + ".fields": l.Columns,
+		prefix + ".sort":   sort,
+	})
+	if err != nil {
+		log.Println("save column layout failed:", err)
+	}
+}
+
+func (a *Application) visibleColumns() []songColumn {
+	var columns []songColumn
+	for _, key := range a.layout().Columns {
+		if col, ok := columnByKey(key); ok {
+			columns = append(columns, col)
+		}
+	}
+	return columns
+}
+
+// applyView 按当前视图的排序和过滤条件生成显示列表，并重新定位正在播放的歌曲
+func (a *Application) applyView() {
+	songs := slices.Clone(a.baseSongs)
+	if keys := a.layout().Sort; len(keys) > 0 {
+		slices.SortStableFunc(songs, func(x, y subsonic.Song) int {
+			for _, key := range keys {
+				col, _ := columnByKey(key.Column)
+				if c := col.Compare(x, y); c != 0 {
+					if key.Desc {
+						return -c
+					}
+					return c
+				}
+			}
+			return 0
+		})
+	}
+
+	// 过滤后按匹配得分排序，得分相同时保持原有顺序
+	a.activeFilter = parseFilter(a.filter)
+	if !a.activeFilter.empty() {
+		type scored struct {
+			song  subsonic.Song
+			score int
+		}
+		matches := make([]scored, 0, len(songs))
+		for _, song := range songs {
+			if score, ok := a.activeFilter.match(song); ok {
+				matches = append(matches, scored{song, score})
+			}
+		}
+		if len(a.activeFilter.terms) > 0 {
+			slices.SortStableFunc(matches, func(x, y scored) int { return cmp.Compare(y.score, x.score) })
+		}
+		songs = songs[:0]
+		for _, m := range matches {
+			songs = append(songs, m.song)
+		}
+	}
+
+	a.totalSongs = songs
+	a.totalPages = (len(a.totalSongs) + a.pageSize - 1) / a.pageSize
+	a.currentPage = max(min(a.currentPage, a.totalPages), 1)
+	if a.currentSong != nil {
+		a.currentSongIndex = a.indexOf(a.currentSong.ID)
+	}
+}
+
+// indexOf 返回歌曲在显示列表中的下标，不存在时返回 -1
+func (a *Application) indexOf(songID string) int {
+	return slices.IndexFunc(a.totalSongs, func(s subsonic.Song) bool { return s.ID == songID })
+}
+
+// sortBy 按列排序：add 为 false 时替换为单列排序，为 true 时追加为次要排序；
+// 已在排序中的列切换升降序，降序后再次选择则取消该列排序
+func (a *Application) sortBy(key string, add bool) {
+	l := a.layout()
+	i := slices.IndexFunc(l.Sort, func(k sortKey) bool { return k.Column == key })
+
+	switch {
+	case i >= 0 && l.Sort[i].Desc:
+		l.Sort = slices.Delete(slices.Clone(l.Sort), i, i+1)
+	case i >= 0:
+		l.Sort = slices.Clone(l.Sort)
+		l.Sort[i].Desc = true
+	case add:
+		l.Sort = append(slices.Clone(l.Sort), sortKey{Column: key})
+	default:
+		l.Sort = []sortKey{{Column: key}}
+	}
+	if !add && i >= 0 && len(l.Sort) > 1 {
+		// 单列排序时只保留这一列
+		l.Sort = slices.DeleteFunc(l.Sort, func(k sortKey) bool { return k.Column != key })
+	}
+	a.relayout()
+}
+
+// relayout 保存布局并重新排序、渲染表格，需要在 UI 线程调用
+func (a *Application) relayout() {
+	a.saveLayout()
+	a.applyView()
+	a.renderSongTable()
+	if a.currentSongIndex >= 0 {
+		a.showSong(a.currentSongIndex)
+	}
+}
+
+// sortColumn 按第 n 个可见列排序（从 1 开始）
+func (a *Application) sortColumn(n int, add bool) {
+	columns := a.visibleColumns()
+	if n >= 1 && n <= len(columns) {
+		a.sortBy(columns[n-1].Key, add)
+	}
+}
+
+// sortMarker 表头中的排序标记，多列排序时显示优先级
+func sortMarker(keys []sortKey, column string) string {
+	for i, key := range keys {
+		if key.Column != column {
+			continue
+		}
+		marker := " ▲"
+		if key.Desc {
+			marker = " ▼"
+		}
+		if len(keys) > 1 {
+			marker += strconv.Itoa(i + 1)
+		}
+		return marker
+	}
+	return ""
+}
+
+// sortText 状态栏中显示的排序说明
+func sortText(keys []sortKey) string {
+	if len(keys) == 0 {
+		return ""
+	}
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		col, _ := columnByKey(key.Column)
+		parts[i] = strings.ToLower(col.Title) + sortMarker([]sortKey{key}, key.Column)
+	}
+	return " · sort: " + strings.Join(parts, ", ")
+}
+
+// showColumns 打开列设置：Enter 显示/隐藏，J/K 调整顺序，s 排序，a 追加排序
+func (a *Application) showColumns() {
+	a.columnsUI(0)
+}
+
+// columnOrder 列设置中的顺序：已显示的列按顺序在前，其余在后
+func columnOrder(l *tableLayout) []string {
+	keys := slices.Clone(l.Columns)
+	for _, col := range songColumns {
+		if !slices.Contains(keys, col.Key) {
+			keys = append(keys, col.Key)
+		}
+	}
+	return keys
+}
+
+func (a *Application) columnsUI(current int) {
+	l := a.layout()
+	list := tview.NewList().ShowSecondaryText(false)
+	list.SetBorder(true).SetTitle(fmt.Sprintf(" Columns: %s (Enter show, J/K move, s sort, a add sort) ", a.view))
+
+	keys := columnOrder(l)
+	for _, key := range keys {
+		col, _ := columnByKey(key)
+		mark := "[darkgray][ ]"
+		if slices.Contains(l.Columns, key) {
+			mark = "[lightgreen][x]"
+		}
+		list.AddItem(fmt.Sprintf("%s [-]%s[yellow]%s", tview.Escape(mark), col.Title, sortMarker(l.Sort, key)), "", 0, nil)
+	}
+	list.SetCurrentItem(current)
+
+	list.SetSelectedFunc(func(index int, _, _ string, _ rune) {
+		key := keys[index]
+		if i := slices.Index(l.Columns, key); i >= 0 {
+			l.Columns = slices.Delete(slices.Clone(l.Columns), i, i+1)
+		} else {
+			l.Columns = append(slices.Clone(l.Columns), key)
+		}
+		a.relayout()
+		a.columnsUI(slices.Index(columnOrder(l), key))
+	})
+
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		index := list.GetCurrentItem()
+		key := keys[index]
+		switch event.Rune() {
+		case 's', 'a':
+			a.sortBy(key, event.Rune() == 'a')
+			a.columnsUI(index)
+			return nil
+		case 'J', 'K':
+			i := slices.Index(l.Columns, key)
+			j := i + 1
+			if event.Rune() == 'K' {
+				j = i - 1
+			}
+			if i < 0 || j < 0 || j >= len(l.Columns) {
+				return nil
+			}
+			l.Columns = slices.Clone(l.Columns)
+			l.Columns[i], l.Columns[j] = l.Columns[j], l.Columns[i]
+			a.relayout()
+			a.columnsUI(j)
+			return nil
+		}
+		return event
+	})
+
+	a.showOverlay("columns", list, 70, len(songColumns)+2)
+}
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/spf13/viper"
+	"github.com/yhkl-dev/NaviCLI/mpvplayer"
+	"github.com/yhkl-dev/NaviCLI/subsonic"
+)
+
+const maxCommandHistory = 200
+
+// libraryIndex 已加载过的艺术家、专辑和流派，用于命令行补全
+type libraryIndex struct {
+	artists map[string]bool
+	albums  map[string]bool
+	genres  map[string]bool
+}
+
+// noteSongs 记录歌曲的艺术家、专辑和流派
+func (a *Application) noteSongs(songs []subsonic.Song) {
+	a.loadingMux.Lock()
+	defer a.loadingMux.Unlock()
+
+	if a.library.artists == nil {
+		a.library = libraryIndex{
+			artists: make(map[string]bool),
+			albums:  make(map[string]bool),
+			genres:  make(map[string]bool),
+		}
+	}
+	for _, song := range songs {
+		if song.Artist != "" {
+			a.library.artists[song.Artist] = true
+		}
+		if song.Album != "" {
+			a.library.albums[song.Album] = true
+		}
+		if song.Genre != "" {
+			a.library.genres[song.Genre] = true
+		}
+	}
+}
+
+func (a *Application) libraryValues(field string) []string {
+	a.loadingMux.Lock()
+	defer a.loadingMux.Unlock()
+
+	var values map[string]bool
+	switch field {
+	case "artist":
+		values = a.library.artists
+	case "album":
+		values = a.library.albums
+	case "genre":
+		values = a.library.genres
+	}
+	result := make([]string, 0, len(values))
+	for value := range values {
+		result = append(result, value)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// showCommandLine 打开命令行：Tab 补全，↑/↓ 浏览历史，Enter 执行
+func (a *Application) showCommandLine() {
+	a.loadCommandHistory()
+	historyIndex := len(a.commandHistory)
+
+	// 播放列表名称用于 playlist add 的补全
+	go func() {
+		playlists, err := a.subsonicClient.ListPlaylistsContext(a.ctx)
+		if err != nil {
+			return
+		}
+		names := make([]string, len(playlists))
+		for i, playlist := range playlists {
+			names[i] = playlist.Name
+			if strings.ContainsAny(playlist.Name, " \t") {
+				names[i] = `"` + playlist.Name + `"`
+			}
+		}
+		a.application.QueueUpdate(func() {
+			a.playlistNames = names
+		})
+	}()
+
+	input := tview.NewInputField().SetLabel(":")
+	input.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyTab:
+			text, candidates := a.complete(input.GetText())
+			input.SetText(text)
+			if len(candidates) > 1 {
+				a.setPromptHint("[darkgray]" + tview.Escape(strings.Join(candidates, "  ")))
+			} else {
+				a.setPromptHint("")
+			}
+			return nil
+		case tcell.KeyUp:
+			if historyIndex > 0 {
+				historyIndex--
+				input.SetText(a.commandHistory[historyIndex])
+			}
+			return nil
+		case tcell.KeyDown:
+			if historyIndex < len(a.commandHistory)-1 {
+				historyIndex++
+				input.SetText(a.commandHistory[historyIndex])
+			} else {
+				historyIndex = len(a.commandHistory)
+				input.SetText("")
+			}
+			return nil
+		}
+		return event
+	})
+	input.SetDoneFunc(func(key tcell.Key) {
+		if key != tcell.KeyEnter {
+			return
+		}
+		line := strings.TrimSpace(input.GetText())
+		a.closePrompt()
+		if line == "" {
+			return
+		}
+		a.addCommandHistory(line)
+		if err := a.runCommand(line); err != nil {
+			a.setMessage("[red]" + tview.Escape(err.Error()))
+		}
+	})
+
+	a.showPrompt("command", input, nil)
+	a.setPromptHint("[darkgray]Tab to complete, ↑/↓ for history, e.g. play artist:Radiohead, vol 40, seek 2:30")
+}
+
+// complete 补全命令行最后一个词，返回补全后的文本和所有候选
+func (a *Application) complete(text string) (string, []string) {
+	words := strings.Fields(text)
+	if len(words) == 0 || (len(words) == 1 && !strings.HasSuffix(text, " ")) {
+		prefix := ""
+		if len(words) == 1 {
+			prefix = words[0]
+		}
+		var names []string
+		for _, act := range a.actions() {
+			names = append(names, act.Name)
+			names = append(names, act.Aliases...)
+		}
+		return completeWord("", prefix, names)
+	}
+
+	// 已输入的部分和正在补全的最后一个词
+	head, last := text, ""
+	if !strings.HasSuffix(text, " ") {
+		i := strings.LastIndex(text, " ")
+		head, last = text[:i+1], text[i+1:]
+	}
+	return completeWord(head, last, a.argumentCandidates(words[0], head, last))
+}
+
+// argumentCandidates 命令参数的补全候选
+func (a *Application) argumentCandidates(command, head, last string) []string {
+	act, ok := a.findAction(command)
+	if !ok {
+		return nil
+	}
+
+	switch act.Name {
+	case "play", "search", "find":
+		field, _, ok := strings.Cut(last, ":")
+		if !ok {
+			return []string{"artist:", "album:", "genre:", "title:", "year:"}
+		}
+		var candidates []string
+		for _, value := range a.libraryValues(field) {
+			if strings.ContainsAny(value, " \t") {
+				value = `"` + value + `"`
+			}
+			candidates = append(candidates, field+":"+value)
+		}
+		return candidates
+	case "queue":
+		return []string{"clear", "add", "next"}
+	case "playlist":
+		if strings.Count(head, " ") <= 1 {
+			return []string{"add"}
+		}
+		return a.playlistNames
+	case "server":
+		return serverNames()
+	case "theme":
+		return themeNames()
+	case "loop":
+		if strings.Count(head, " ") <= 1 {
+			return []string{"a", "b", "off", "save", "load", "delete"}
+		}
+		return a.songLoopNames()
+	case "sleep":
+		return sleepPresets
+	case "alarm":
+		if strings.Count(head, " ") <= 1 {
+			return []string{"off"}
+		}
+		return a.playlistNames
+	case "device":
+		return a.audioDeviceNames()
+	case "replaygain":
+		switch strings.Fields(head)[len(strings.Fields(head))-1] {
+		case "clip", "loudnorm":
+			return []string{"on", "off"}
+		}
+		return append(slices.Clone(replayGainModes), "preamp", "clip", "loudnorm")
+	case "eq":
+		candidates := []string{"night", "mono"}
+		for _, name := range mpvplayer.PresetNames() {
+			if strings.Contains(name, " ") {
+				name = `"` + name + `"`
+			}
+			candidates = append(candidates, name)
+		}
+		return candidates
+	case "sort":
+		keys := make([]string, len(songColumns))
+		for i, col := range songColumns {
+			keys[i] = col.Key
+		}
+		return keys
+	}
+	return nil
+}
+
+// completeWord 用候选补全 prefix：唯一匹配时补全并加空格，多个匹配时补全到公共前缀
+func completeWord(head, prefix string, candidates []string) (string, []string) {
+	var matches []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(strings.ToLower(candidate), strings.ToLower(prefix)) && !slices.Contains(matches, candidate) {
+			matches = append(matches, candidate)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return head + prefix, nil
+	case 1:
+		suffix := " "
+		if strings.HasSuffix(matches[0], ":") {
+			suffix = ""
+		}
+		return head + matches[0] + suffix, matches
+	}
+
+	common := matches[0]
+	for _, match := range matches[1:] {
+		for !strings.HasPrefix(strings.ToLower(match), strings.ToLower(common)) {
+			common = common[:len(common)-1]
+		}
+	}
+	if len(common) < len(prefix) {
+		common = prefix
+	}
+	return head + common, matches
+}
+
+func (a *Application) loadCommandHistory() {
+	if a.commandHistory != nil {
+		return
+	}
+	a.commandHistory = []string{}
+
+	file, err := os.Open(dataPath("command_history"))
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			a.commandHistory = append(a.commandHistory, line)
+		}
+	}
+}
+
+// addCommandHistory 记录命令并写回历史文件，连续重复的命令只保留一条
+func (a *Application) addCommandHistory(line string) {
+	if n := len(a.commandHistory); n > 0 && a.commandHistory[n-1] == line {
+		return
+	}
+	a.commandHistory = append(a.commandHistory, line)
+	if len(a.commandHistory) > maxCommandHistory {
+		a.commandHistory = a.commandHistory[len(a.commandHistory)-maxCommandHistory:]
+	}
+
+	path := dataPath("command_history")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	os.WriteFile(path, []byte(strings.Join(a.commandHistory, "\n")+"\n"), 0o600)
+}
+
+// serverNames 配置中 [servers.<name>] 的服务器名称，default 表示 [server]
+func serverNames() []string {
+	names := []string{"default"}
+	for name := range viper.GetStringMap("servers") {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return names
+}
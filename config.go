@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+)
+
+// stateFile 保存在界面中修改的设置（主题、音效、ReplayGain、淡入淡出、列布局等），
+// 用户手写的 config.toml 保持只读，注释和键的顺序不会被改写
+const stateFile = "state.toml"
+
+// configPath 返回配置文件路径，首次运行尚未创建时为 ~/.config/config.toml
+func configPath() (string, error) {
+	if path := viper.ConfigFileUsed(); path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "config.toml"), nil
+}
+
+// loadState 读取界面中保存的设置，覆盖 config.toml 中的同名设置
+func loadState() {
+	state := viper.New()
+	state.SetConfigFile(dataPath(stateFile))
+	state.SetConfigType("toml")
+	if err := state.ReadInConfig(); err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Println("read saved settings failed:", err)
+		}
+		return
+	}
+	for _, key := range state.AllKeys() {
+		viper.Set(key, state.Get(key))
+	}
+}
+
+// saveSettings 更新内存中的配置并写入状态文件，下次启动时由 loadState 读回
+func saveSettings(values map[string]any) error {
+	for key, value := range values {
+		viper.Set(key, value)
+	}
+	return writeSettings(dataPath(stateFile), values)
+}
+
+// saveConfig 首次运行时把服务器信息写入 config.toml，返回文件路径
+func saveConfig(values map[string]any) (string, error) {
+	path, err := configPath()
+	if err != nil {
+		return "", err
+	}
+	for key, value := range values {
+		viper.Set(key, value)
+	}
+	if err := writeSettings(path, values); err != nil {
+		return "", err
+	}
+	viper.SetConfigFile(path)
+	return path, nil
+}
+
+// writeSettings 只把 values 中的键合并进 path 指向的 TOML 文件。
+// viper.WriteConfig 会把 ViperInit 中 SetDefault 的默认值一并写入，
+// 之后默认值的调整就到不了已有用户，所以这里另用一个只读取该文件的 viper
+func writeSettings(path string, values map[string]any) error {
+	file := viper.New()
+	file.SetConfigFile(path)
+	file.SetConfigType("toml")
+	// 配置文件包含服务器密码，新建时只允许当前用户读写
+	file.SetConfigPermissions(0o600)
+	if err := file.ReadInConfig(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	for key, value := range values {
+		file.Set(key, value)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return file.WriteConfig()
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestSaveSettingsLeavesConfigUntouched(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	t.Setenv("HOME", t.TempDir())
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	original := "# my server\n[server]\nurl = 'http://music.local'\n\n[ui]\ntheme = 'dark' # default\n"
+	if err := os.WriteFile(path, []byte(original), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	viper.SetConfigFile(path)
+	viper.SetDefault("speed.podcast", 1.5)
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := saveSettings(map[string]any{"ui.theme": "light"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := viper.GetString("ui.theme"); got != "light" {
+		t.Errorf("ui.theme = %q, want light", got)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != original {
+		t.Errorf("config.toml was rewritten:\n%s", data)
+	}
+
+	state, err := os.ReadFile(dataPath(stateFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(state), "theme = 'light'") || strings.Contains(string(state), "speed") {
+		t.Errorf("unexpected state file:\n%s", state)
+	}
+
+	// 下次启动时状态文件覆盖 config.toml 中的设置
+	viper.Reset()
+	viper.SetConfigFile(path)
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	loadState()
+	if got := viper.GetString("ui.theme"); got != "light" {
+		t.Errorf("ui.theme after loadState = %q, want light", got)
+	}
+	if got := viper.GetString("server.url"); got != "http://music.local" {
+		t.Errorf("server.url after loadState = %q", got)
+	}
+}
+
+func TestSaveConfigCreatesPrivateFile(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	path := filepath.Join(t.TempDir(), ".config", "config.toml")
+	viper.SetConfigFile(path)
+	viper.SetDefault("speed.podcast", 1.5)
+
+	got, err := saveConfig(map[string]any{"server.url": "http://music.local", "server.password": "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != path {
+		t.Errorf("path = %q, want %q", got, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "url = 'http://music.local'") || strings.Contains(string(data), "speed") {
+		t.Errorf("unexpected config:\n%s", data)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("config permissions = %o, want 600", perm)
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/wildeyedskies/go-mpv/mpv"
+	"github.com/yhkl-dev/NaviCLI/mpvplayer"
+	"github.com/yhkl-dev/NaviCLI/subsonic"
+)
+
+// crossfadeDuration 配置 crossfade.seconds，限制在 0 到 12 秒
+func crossfadeDuration() time.Duration {
+	seconds := max(min(viper.GetFloat64("crossfade.seconds"), mpvplayer.MaxCrossfade.Seconds()), 0)
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// setupCrossfade 按配置创建淡出用的第二个 mpv 实例并在主实例上安装淡入滤镜，关闭时移除
+func (a *Application) setupCrossfade() {
+	if a.mpvInstance == nil || a.mpvInstance.Mpv == nil {
+		return
+	}
+	enabled := crossfadeDuration() > 0
+	if enabled && a.crossfader == nil {
+		crossfader, err := mpvplayer.NewCrossfader(playerOptions())
+		if err != nil {
+			log.Println("create crossfade player failed:", err)
+			return
+		}
+		a.crossfader = crossfader
+	}
+	if !enabled && a.crossfader != nil {
+		a.crossfader.Stop(a.mpvInstance)
+	}
+	if err := a.mpvInstance.EnableCrossfade(enabled); err != nil {
+		log.Println("set crossfade filter failed:", err)
+	}
+}
+
+// cancelCrossfade 切到其他歌曲或暂停时停止正在淡出的上一首，songID 是淡入的歌曲时保留
+func (a *Application) cancelCrossfade(songID string) {
+	if a.crossfader == nil || a.mpvInstance == nil {
+		return
+	}
+	if next := a.crossfader.Next(); next != "" && next != songID {
+		a.crossfader.Stop(a.mpvInstance)
+	}
+}
+
+// peekNextSong 自动播放的下一首：队列中的第一首，否则列表中的下一首
+func (a *Application) peekNextSong() (subsonic.Song, bool) {
+	a.loadingMux.Lock()
+	defer a.loadingMux.Unlock()
+	if len(a.queue) > 0 {
+		return a.queue[0], true
+	}
+	next := a.currentSongIndex + 1
+	if next >= len(a.totalSongs) {
+		// 电台模式会补充歌曲，不回到开头
+		if a.radioMode || len(a.totalSongs) == 0 {
+			return subsonic.Song{}, false
+		}
+		next = 0
+	}
+	return a.totalSongs[next], true
+}
+
+// gapless 同一专辑的连续曲目不做淡入淡出，保留无缝衔接
+func gapless(current, next subsonic.Song) bool {
+	return current.AlbumID != "" && current.AlbumID == next.AlbumID && next.Track == current.Track+1
+}
+
+// watchCrossfade 歌曲剩余时间少于淡入淡出时长时，副实例接着播放结尾，主实例切到下一首
+func (a *Application) watchCrossfade() {
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+	faded := ""
+
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		fade := crossfadeDuration()
+		if fade <= 0 || a.crossfader == nil || a.mpvInstance == nil || a.mpvInstance.Mpv == nil {
+			continue
+		}
+
+		a.loadingMux.Lock()
+		song := a.currentSong
+		ready := song != nil && a.currentStation == nil && a.isPlaying && !a.isLoading
+		offset := a.playOffset
+		speed := a.speed
+		// 睡眠定时器在歌曲结束时停止播放，不切到下一首
+		sleeping := a.sleep != nil && a.sleep.mode != sleepAfterTime
+		looping := a.loopB >= 0
+		a.loadingMux.Unlock()
+		if !ready || sleeping || looping || song.ID == faded {
+			continue
+		}
+		if speed <= 0 {
+			speed = 1
+		}
+
+		pos, err := a.mpvInstance.GetProperty("time-pos", mpv.FORMAT_DOUBLE)
+		if err != nil {
+			continue
+		}
+		duration, err := a.mpvInstance.GetProperty("duration", mpv.FORMAT_DOUBLE)
+		if err != nil {
+			continue
+		}
+		position := pos.(float64) + offset
+		remaining := (duration.(float64) - pos.(float64)) / speed
+		if remaining > fade.Seconds() || remaining < 1 {
+			continue
+		}
+
+		next, ok := a.peekNextSong()
+		faded = song.ID
+		if !ok || gapless(*song, next) {
+			continue
+		}
+
+		url, serverSeek := a.streamURL(song.ID, int(position))
+		start := position
+		if serverSeek {
+			start = position - float64(int(position))
+		}
+		if err := a.crossfader.Start(a.mpvInstance, url, start, next.ID); err != nil {
+			log.Println("start crossfade failed:", err)
+			continue
+		}
+
+		a.recordPlay(playCompleted)
+		a.application.QueueUpdateDraw(func() {
+			a.playNextSong()
+		})
+		go a.crossfader.Run(a.mpvInstance, time.Duration(remaining*float64(time.Second)))
+	}
+}
+
+// crossfadeCommand 设置淡入淡出时长（秒，0 到 12），off 关闭，写回配置 crossfade.seconds
+func (a *Application) crossfadeCommand(arg string) error {
+	if arg == "" {
+		a.setMessage(fmt.Sprintf("[lightgreen]crossfade: %gs", crossfadeDuration().Seconds()))
+		return nil
+	}
+	if arg == "off" {
+		arg = "0"
+	}
+	seconds, err := strconv.ParseFloat(arg, 64)
+	if err != nil || seconds < 0 || seconds > mpvplayer.MaxCrossfade.Seconds() {
+		return fmt.Errorf("invalid crossfade: %q (0 to %g seconds)", arg, mpvplayer.MaxCrossfade.Seconds())
+	}
+
+	if err := saveSettings(map[string]any{"crossfade.seconds": seconds}); err != nil {
+		log.Println("save crossfade failed:", err)
+	}
+	a.setupCrossfade()
+	if seconds == 0 {
+		a.setMessage("[lightgreen]crossfade off")
+	} else {
+		a.setMessage(fmt.Sprintf("[lightgreen]crossfade %gs", seconds))
+	}
+	return nil
+}
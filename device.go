@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/rivo/tview"
+	"github.com/spf13/viper"
+	"github.com/yhkl-dev/NaviCLI/mpvplayer"
+)
+
+// showAudioDevices 选择音频输出设备，切换后当前歌曲继续播放
+func (a *Application) showAudioDevices() {
+	if a.mpvInstance == nil || a.mpvInstance.Mpv == nil {
+		return
+	}
+	devices, err := a.mpvInstance.AudioDevices()
+	if err != nil {
+		a.setMessage("[red]list audio devices failed: " + err.Error())
+		return
+	}
+	current := a.mpvInstance.AudioDevice()
+
+	list := tview.NewList()
+	list.SetBorder(true).SetTitle(" Audio Output ")
+	selected := 0
+	for i, device := range devices {
+		mark := "  "
+		if device.Name == current {
+			mark = "* "
+			selected = i
+		}
+		list.AddItem(mark+tview.Escape(device.Description), "[darkgray]"+tview.Escape(device.Name), 0, nil)
+	}
+	list.SetCurrentItem(selected)
+	list.SetSelectedFunc(func(index int, _, _ string, _ rune) {
+		a.closeOverlay()
+		if err := a.setAudioDevice(devices[index].Name); err != nil {
+			a.setMessage("[red]switch audio device failed: " + err.Error())
+		}
+	})
+
+	a.showOverlay("devices", list, 70, min(len(devices)*2+2, 24))
+}
+
+// setAudioDevice 切换输出设备并写回配置 player.audio_device，再应用该设备保存的音效
+func (a *Application) setAudioDevice(name string) error {
+	if err := a.mpvInstance.SetAudioDevice(name); err != nil {
+		return err
+	}
+	if err := saveSettings(map[string]any{"player.audio_device": name}); err != nil {
+		log.Println("save audio device failed:", err)
+	}
+	a.loadEffects()
+	a.setMessage("[lightgreen]audio output: " + tview.Escape(name))
+	return nil
+}
+
+// deviceCommand 无参数时打开设备列表，否则按名称切换
+func (a *Application) deviceCommand(name string) error {
+	if a.mpvInstance == nil || a.mpvInstance.Mpv == nil {
+		return fmt.Errorf("player is not available")
+	}
+	if name == "" {
+		a.showAudioDevices()
+		return nil
+	}
+	devices, err := a.mpvInstance.AudioDevices()
+	if err != nil {
+		return err
+	}
+	for _, device := range devices {
+		if device.Name == name {
+			return a.setAudioDevice(name)
+		}
+	}
+	return fmt.Errorf("unknown audio device: %s", name)
+}
+
+// audioDeviceNames 命令行补全用的设备名称
+func (a *Application) audioDeviceNames() []string {
+	if a.mpvInstance == nil || a.mpvInstance.Mpv == nil {
+		return nil
+	}
+	devices, err := a.mpvInstance.AudioDevices()
+	if err != nil {
+		return nil
+	}
+	names := make([]string, len(devices))
+	for i, device := range devices {
+		names[i] = device.Name
+	}
+	return names
+}
+
+// playerOptions 读取配置 player.* 中的音频输出选项
+func playerOptions() mpvplayer.Options {
+	return mpvplayer.Options{
+		AudioDevice: viper.GetString("player.audio_device"),
+		Exclusive:   viper.GetBool("player.audio_exclusive"),
+		Buffer:      viper.GetFloat64("player.audio_buffer"),
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/spf13/viper"
+	"github.com/yhkl-dev/NaviCLI/mpvplayer"
+)
+
+// effectsPrefix 当前输出设备的音效配置前缀 effects.<device>，设备名中的符号替换为 _
+func (a *Application) effectsPrefix() string {
+	device := "auto"
+	if a.mpvInstance != nil && a.mpvInstance.Mpv != nil {
+		device = a.mpvInstance.AudioDevice()
+	}
+	key := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' {
+			return r
+		}
+		return '_'
+	}, device)
+	return "effects." + key
+}
+
+// loadEffects 读取当前输出设备的音效设置并应用
+func (a *Application) loadEffects() {
+	prefix := a.effectsPrefix()
+	var e mpvplayer.Effects
+	for i, gain := range viper.GetIntSlice(prefix + ".gains") {
+		if i < len(e.Gains) {
+			e.Gains[i] = max(min(gain, mpvplayer.MaxGain), mpvplayer.MinGain)
+		}
+	}
+	e.Compressor = viper.GetBool(prefix + ".compressor")
+	e.Mono = viper.GetBool(prefix + ".mono")
+	a.applyEffects(e, false)
+}
+
+// applyEffects 应用音效，save 为 true 时写回当前输出设备的配置
+func (a *Application) applyEffects(e mpvplayer.Effects, save bool) {
+	a.effects = e
+	if a.mpvInstance != nil && a.mpvInstance.Mpv != nil {
+		if err := a.mpvInstance.SetEffects(e); err != nil {
+			log.Println("apply audio effects failed:", err)
+		}
+	}
+	if !save {
+		return
+	}
+
+	prefix := a.effectsPrefix()
+	err := saveSettings(map[string]any{
+		prefix + ".gains":      e.Gains[:],
+		prefix + ".compressor": e.Compressor,
+		prefix + ".mono":       e.Mono,
+	})
+	if err != nil {
+		log.Println("save audio effects failed:", err)
+	}
+}
+
+// eqCommand 无参数时打开均衡器面板，也可以直接设置预设或切换 night、mono
+func (a *Application) eqCommand(arg string) error {
+	arg = strings.Trim(arg, `"`)
+	e := a.effects
+	switch arg {
+	case "":
+		a.showEqualizer()
+		return nil
+	case "night":
+		e.Compressor = !e.Compressor
+	case "mono":
+		e.Mono = !e.Mono
+	default:
+		gains, ok := mpvplayer.Presets[arg]
+		if !ok {
+			return fmt.Errorf("unknown preset: %s", arg)
+		}
+		e.Gains = gains
+	}
+	a.applyEffects(e, true)
+	a.setMessage("[lightgreen]" + effectsSummary(e))
+	return nil
+}
+
+func effectsSummary(e mpvplayer.Effects) string {
+	parts := []string{"eq: custom"}
+	if preset := e.Preset(); preset != "" {
+		parts[0] = "eq: " + preset
+	}
+	if e.Compressor {
+		parts = append(parts, "night")
+	}
+	if e.Mono {
+		parts = append(parts, "mono")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// showEqualizer 均衡器面板：←/→ 选择频段，↑/↓ 调整增益，p 切换预设，
+// n 夜间模式（动态范围压缩），m 单声道，0 当前频段归零。
+// 调整立即生效，关闭面板时才写回配置
+func (a *Application) showEqualizer() {
+	band := 0
+	changed := false
+	view := tview.NewTextView().SetDynamicColors(true)
+	view.SetBorder(true).SetTitle(" Equalizer (ESC to close) ")
+
+	render := func() {
+		view.SetText(renderEqualizer(a.effects, band))
+	}
+	update := func(e mpvplayer.Effects) {
+		a.applyEffects(e, false)
+		changed = true
+		render()
+	}
+
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		e := a.effects
+		switch event.Key() {
+		case tcell.KeyLeft:
+			band = max(band-1, 0)
+			render()
+			return nil
+		case tcell.KeyRight:
+			band = min(band+1, len(e.Gains)-1)
+			render()
+			return nil
+		case tcell.KeyUp:
+			e.Gains[band] = min(e.Gains[band]+1, mpvplayer.MaxGain)
+			update(e)
+			return nil
+		case tcell.KeyDown:
+			e.Gains[band] = max(e.Gains[band]-1, mpvplayer.MinGain)
+			update(e)
+			return nil
+		}
+
+		switch event.Rune() {
+		case 'h':
+			band = max(band-1, 0)
+			render()
+		case 'l':
+			band = min(band+1, len(e.Gains)-1)
+			render()
+		case 'k':
+			e.Gains[band] = min(e.Gains[band]+1, mpvplayer.MaxGain)
+			update(e)
+		case 'j':
+			e.Gains[band] = max(e.Gains[band]-1, mpvplayer.MinGain)
+			update(e)
+		case '0':
+			e.Gains[band] = 0
+			update(e)
+		case 'p':
+			names := mpvplayer.PresetNames()
+			next := (slices.Index(names, e.Preset()) + 1) % len(names)
+			e.Gains = mpvplayer.Presets[names[next]]
+			update(e)
+		case 'n':
+			e.Compressor = !e.Compressor
+			update(e)
+		case 'm':
+			e.Mono = !e.Mono
+			update(e)
+		default:
+			return event
+		}
+		return nil
+	})
+
+	render()
+	a.showOverlay("equalizer", view, 64, 22)
+	a.searchMux.Lock()
+	a.overlayClosed = func() {
+		if changed {
+			a.applyEffects(a.effects, true)
+		}
+	}
+	a.searchMux.Unlock()
+}
+
+// renderEqualizer 每个频段一行滑块，selected 为当前选中的频段
+func renderEqualizer(e mpvplayer.Effects, selected int) string {
+	var b strings.Builder
+	preset := e.Preset()
+	if preset == "" {
+		preset = "custom"
+	}
+	fmt.Fprintf(&b, "[gray]Preset: [lightgreen]%s\n\n", preset)
+
+	for i, gain := range e.Gains {
+		freq := fmt.Sprintf("%gHz", mpvplayer.EqualizerBands[i])
+		if mpvplayer.EqualizerBands[i] >= 1000 {
+			freq = fmt.Sprintf("%gkHz", mpvplayer.EqualizerBands[i]/1000)
+		}
+
+		var slider strings.Builder
+		for level := mpvplayer.MinGain; level <= mpvplayer.MaxGain; level++ {
+			switch {
+			case level == gain:
+				slider.WriteString("●")
+			case level == 0:
+				slider.WriteString("┼")
+			default:
+				slider.WriteString("─")
+			}
+		}
+
+		color := "[gray]"
+		if i == selected {
+			color = "[yellow]"
+		}
+		fmt.Fprintf(&b, "%s%7s  %s  %+3d dB\n", color, freq, slider.String(), gain)
+	}
+
+	fmt.Fprintf(&b, "\n[gray]Night mode (compression): %s\n", onOff(e.Compressor))
+	fmt.Fprintf(&b, "[gray]Mono downmix:             %s\n", onOff(e.Mono))
+	b.WriteString("\n[darkgray]←/→ band  ↑/↓ gain  0 reset band  p preset  n night  m mono")
+	return b.String()
+}
+
+func onOff(on bool) string {
+	if on {
+		return "[lightgreen]on"
+	}
+	return "[darkgray]off"
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/yhkl-dev/NaviCLI/subsonic"
+)
+
+// fuzzyColumns 不带前缀的关键词在这些列中模糊匹配
+var fuzzyColumns = []string{"title", "artist", "album"}
+
+// fieldTerm 带前缀的关键词，如 artist:radiohead
+type fieldTerm struct {
+	column string
+	value  string
+}
+
+// songFilter 过滤条件：模糊关键词、字段前缀和年份范围（year:2010、year:>2010、year:2000-2010）
+type songFilter struct {
+	terms    []string
+	fields   []fieldTerm
+	yearFrom int
+	yearTo   int
+}
+
+func parseFilter(query string) songFilter {
+	var f songFilter
+	for _, token := range splitQuery(query) {
+		name, value, ok := strings.Cut(token, ":")
+		switch {
+		case ok && name == "year":
+			f.parseYear(value)
+		case ok && value != "" && slices.Contains([]string{"title", "artist", "album", "genre"}, name):
+			f.fields = append(f.fields, fieldTerm{column: name, value: strings.ToLower(value)})
+		default:
+			f.terms = append(f.terms, strings.ToLower(token))
+		}
+	}
+	return f
+}
+
+// splitQuery 按空格拆分，双引号内的空格保留，如 album:"ok computer"
+func splitQuery(query string) []string {
+	var tokens []string
+	var current strings.Builder
+	quoted := false
+	for _, r := range query {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case unicode.IsSpace(r) && !quoted:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens
+}
+
+func (f *songFilter) parseYear(value string) {
+	atoi := func(s string) int {
+		n, _ := strconv.Atoi(strings.TrimSpace(s))
+		return n
+	}
+	switch {
+	case strings.HasPrefix(value, ">="):
+		f.yearFrom = atoi(value[2:])
+	case strings.HasPrefix(value, "<="):
+		f.yearTo = atoi(value[2:])
+	case strings.HasPrefix(value, ">"):
+		f.yearFrom = atoi(value[1:]) + 1
+	case strings.HasPrefix(value, "<"):
+		f.yearTo = atoi(value[1:]) - 1
+	case strings.Contains(value, "-"):
+		from, to, _ := strings.Cut(value, "-")
+		f.yearFrom, f.yearTo = atoi(from), atoi(to)
+	default:
+		f.yearFrom = atoi(value)
+		f.yearTo = f.yearFrom
+	}
+}
+
+func (f songFilter) empty() bool {
+	return len(f.terms) == 0 && len(f.fields) == 0 && f.yearFrom == 0 && f.yearTo == 0
+}
+
+func columnText(column string, song subsonic.Song) string {
+	switch column {
+	case "title":
+		return song.Title
+	case "artist":
+		return song.Artist
+	case "album":
+		return song.Album
+	case "genre":
+		return song.Genre
+	}
+	return ""
+}
+
+// match 判断歌曲是否满足所有条件，返回模糊匹配得分
+func (f songFilter) match(song subsonic.Song) (int, bool) {
+	if f.yearFrom > 0 && song.Year < f.yearFrom {
+		return 0, false
+	}
+	if f.yearTo > 0 && (song.Year == 0 || song.Year > f.yearTo) {
+		return 0, false
+	}
+
+	total := 0
+	for _, field := range f.fields {
+		score, _, ok := fuzzyMatch(columnText(field.column, song), field.value)
+		if !ok {
+			return 0, false
+		}
+		total += score
+	}
+
+	for _, term := range f.terms {
+		best, found := 0, false
+		for _, column := range fuzzyColumns {
+			if score, _, ok := fuzzyMatch(columnText(column, song), term); ok && (!found || score > best) {
+				best, found = score, true
+			}
+		}
+		if !found {
+			return 0, false
+		}
+		total += best
+	}
+	return total, true
+}
+
+// highlight 转义文本并高亮匹配的字符
+func (f songFilter) highlight(column, text string) string {
+	var patterns []string
+	if slices.Contains(fuzzyColumns, column) {
+		patterns = append(patterns, f.terms...)
+	}
+	for _, field := range f.fields {
+		if field.column == column {
+			patterns = append(patterns, field.value)
+		}
+	}
+
+	matched := make(map[int]bool)
+	for _, pattern := range patterns {
+		if _, positions, ok := fuzzyMatch(text, pattern); ok {
+			for _, pos := range positions {
+				matched[pos] = true
+			}
+		}
+	}
+	if len(matched) == 0 {
+		return tview.Escape(text)
+	}
+
+	var b strings.Builder
+	for i, r := range []rune(text) {
+		if matched[i] {
+			b.WriteString("[yellow::b]" + tview.Escape(string(r)) + "[-::-]")
+		} else {
+			b.WriteString(tview.Escape(string(r)))
+		}
+	}
+	return b.String()
+}
+
+// 模糊匹配的得分，参考 fzf：连续匹配和单词开头的字符加分，中间跳过的字符扣分
+const (
+	scoreMatch       = 16
+	bonusConsecutive = 8
+	bonusWordStart   = 10
+	bonusFirstChar   = 6
+	penaltyGap       = 1
+)
+
+// fuzzyMatch 判断 pattern 是否按顺序出现在 text 中（忽略大小写），
+// 从每个可能的起点尝试并返回得分最高的匹配位置（以 rune 为单位）
+func fuzzyMatch(text, pattern string) (int, []int, bool) {
+	needle := []rune(pattern)
+	if len(needle) == 0 {
+		return 0, nil, true
+	}
+	haystack := []rune(strings.ToLower(text))
+
+	bestScore, found := 0, false
+	var best []int
+	for start, r := range haystack {
+		if r != needle[0] {
+			continue
+		}
+		score, positions, ok := matchFrom(haystack, needle, start)
+		if ok && (!found || score > bestScore) {
+			bestScore, best, found = score, positions, true
+		}
+	}
+	return bestScore, best, found
+}
+
+func matchFrom(haystack, needle []rune, start int) (int, []int, bool) {
+	positions := make([]int, 0, len(needle))
+	score := 0
+	j := 0
+	for i := start; i < len(haystack) && j < len(needle); i++ {
+		if haystack[i] != needle[j] {
+			if j > 0 {
+				score -= penaltyGap
+			}
+			continue
+		}
+
+		score += scoreMatch
+		if i == 0 {
+			score += bonusFirstChar
+		}
+		if i == 0 || !unicode.IsLetter(haystack[i-1]) && !unicode.IsDigit(haystack[i-1]) {
+			score += bonusWordStart
+		}
+		if len(positions) > 0 && positions[len(positions)-1] == i-1 {
+			score += bonusConsecutive
+		}
+		positions = append(positions, i)
+		j++
+	}
+	return score, positions, j == len(needle)
+}
+
+// showFilter 在表格下方打开过滤输入框，输入时实时过滤当前列表。
+// Enter 保留过滤结果，Esc 恢复完整列表和原来选中的歌曲
+func (a *Application) showFilter() {
+	if a.filter == "" {
+		a.filterRestore = ""
+		if song, ok := a.selectedSong(); ok {
+			a.filterRestore = song.ID
+		}
+	}
+
+	input := tview.NewInputField().
+		SetLabel("Filter: ").
+		SetText(a.filter).
+		SetPlaceholder("words, artist:, album:, genre:, year:>2010")
+	input.SetChangedFunc(func(text string) {
+		a.setFilter(text)
+	})
+	input.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			a.closePrompt()
+		}
+	})
+
+	a.showPrompt("filter", input, a.clearFilter)
+}
+
+func (a *Application) setFilter(text string) {
+	a.filter = text
+	a.applyView()
+	a.renderSongTable()
+	if a.songTable.GetRowCount() > 1 {
+		a.songTable.Select(1, 0)
+	}
+}
+
+// clearFilter 清除过滤并恢复打开过滤前选中的歌曲
+func (a *Application) clearFilter() {
+	a.filter = ""
+	a.applyView()
+	a.renderSongTable()
+	if index := a.indexOf(a.filterRestore); index >= 0 {
+		a.showSong(index)
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yhkl-dev/NaviCLI/subsonic"
+)
+
+func TestSplitQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", nil},
+		{"  radiohead  ", []string{"radiohead"}},
+		{"radio head", []string{"radio", "head"}},
+		{`album:"ok computer" year:1997`, []string{"album:ok computer", "year:1997"}},
+		{`"exit music"`, []string{"exit music"}},
+		// 未闭合的引号延续到末尾
+		{`artist:"sigur ros`, []string{"artist:sigur ros"}},
+		{"a\tb", []string{"a", "b"}},
+	}
+	for _, tt := range tests {
+		if got := splitQuery(tt.query); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitQuery(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		query string
+		want  songFilter
+	}{
+		{"", songFilter{}},
+		{"Radiohead", songFilter{terms: []string{"radiohead"}}},
+		{`artist:Radiohead album:"OK Computer"`, songFilter{fields: []fieldTerm{
+			{column: "artist", value: "radiohead"},
+			{column: "album", value: "ok computer"},
+		}}},
+		// 未知前缀和空值按普通关键词处理
+		{"foo:bar", songFilter{terms: []string{"foo:bar"}}},
+		{"artist:", songFilter{terms: []string{"artist:"}}},
+		{"year:1997", songFilter{yearFrom: 1997, yearTo: 1997}},
+		{"year:>2010", songFilter{yearFrom: 2011}},
+		{"year:>=2010", songFilter{yearFrom: 2010}},
+		{"year:<2000", songFilter{yearTo: 1999}},
+		{"year:<=2000", songFilter{yearTo: 2000}},
+		{"year:2000-2010", songFilter{yearFrom: 2000, yearTo: 2010}},
+		{"year:2000-", songFilter{yearFrom: 2000}},
+		{"year:-2010", songFilter{yearTo: 2010}},
+		{"year:abc", songFilter{}},
+		{"genre:jazz year:1959 blue", songFilter{
+			terms:    []string{"blue"},
+			fields:   []fieldTerm{{column: "genre", value: "jazz"}},
+			yearFrom: 1959,
+			yearTo:   1959,
+		}},
+	}
+	for _, tt := range tests {
+		if got := parseFilter(tt.query); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseFilter(%q) = %+v, want %+v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	song := subsonic.Song{Title: "Paranoid Android", Artist: "Radiohead", Album: "OK Computer", Genre: "Rock", Year: 1997}
+	noYear := subsonic.Song{Title: "Paranoid Android", Artist: "Radiohead"}
+
+	tests := []struct {
+		query string
+		song  subsonic.Song
+		want  bool
+	}{
+		{"", song, true},
+		{"paranoid", song, true},
+		{"prnd", song, true},
+		{"radiohead computer", song, true},
+		{"radiohead jazz", song, false},
+		{"artist:radio", song, true},
+		{"artist:android", song, false},
+		{`album:"ok comp"`, song, true},
+		{"genre:rock", song, true},
+		{"year:1997", song, true},
+		{"year:>1997", song, false},
+		{"year:1990-2000", song, true},
+		{"year:<=1996", song, false},
+		{"year:>1990", noYear, false},
+		{"year:<2000", noYear, false},
+	}
+	for _, tt := range tests {
+		if _, got := parseFilter(tt.query).match(tt.song); got != tt.want {
+			t.Errorf("match(%q, %q) = %v, want %v", tt.query, tt.song.Title, got, tt.want)
+		}
+	}
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		text, pattern string
+		positions     []int
+		ok            bool
+	}{
+		{"Radiohead", "", nil, true},
+		{"Radiohead", "radio", []int{0, 1, 2, 3, 4}, true},
+		{"Radiohead", "rdh", []int{0, 2, 5}, true},
+		{"Radiohead", "hr", nil, false},
+		{"Radiohead", "radioheads", nil, false},
+		// 选择得分最高的起点：单词开头的 head 优于 the 中的 h
+		{"the head", "head", []int{4, 5, 6, 7}, true},
+		{"Sigur Rós", "rós", []int{6, 7, 8}, true},
+	}
+	for _, tt := range tests {
+		_, positions, ok := fuzzyMatch(tt.text, tt.pattern)
+		if ok != tt.ok || (ok && !reflect.DeepEqual(positions, tt.positions)) {
+			t.Errorf("fuzzyMatch(%q, %q) = %v, %v; want %v, %v", tt.text, tt.pattern, positions, ok, tt.positions, tt.ok)
+		}
+	}
+}
+
+func TestFuzzyMatchScore(t *testing.T) {
+	// 连续匹配、单词开头的匹配得分更高
+	better := []struct{ text, worse, pattern string }{
+		{"Karma Police", "Black Star", "kar"},
+		{"OK Computer", "Book Computer", "ok"},
+		{"Airbag", "Lucky Airbags Remix", "airbag"},
+	}
+	for _, tt := range better {
+		high, _, ok1 := fuzzyMatch(tt.text, tt.pattern)
+		low, _, ok2 := fuzzyMatch(tt.worse, tt.pattern)
+		if !ok1 || !ok2 || high <= low {
+			t.Errorf("score(%q, %q) = %d should beat score(%q) = %d", tt.text, tt.pattern, high, tt.worse, low)
+		}
+	}
+}
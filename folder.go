@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rivo/tview"
+	"github.com/spf13/viper"
+	"github.com/yhkl-dev/NaviCLI/subsonic"
+)
+
+// applyDefaultMusicFolder 按当前服务器配置的 music_folder（名称或 ID）设置默认音乐库
+func (a *Application) applyDefaultMusicFolder() error {
+	name := viper.GetString(a.serverPrefix + ".music_folder")
+	if name == "" {
+		return nil
+	}
+
+	folders, err := a.subsonicClient.GetMusicFoldersContext(a.ctx)
+	if err != nil {
+		return fmt.Errorf("load music folders failed: %w", err)
+	}
+	for _, folder := range folders {
+		id := strconv.Itoa(folder.ID)
+		if id == name || strings.EqualFold(folder.Name, name) {
+			a.subsonicClient.SetMusicFolder(id)
+			return nil
+		}
+	}
+	return fmt.Errorf("music folder not found: %s", name)
+}
+
+// musicFolderOptions 返回音乐库下拉选项及当前选中项，第一项为全部音乐库
+func (a *Application) musicFolderOptions(folders []subsonic.MusicFolder) ([]string, int) {
+	current := a.subsonicClient.MusicFolder()
+	options := []string{"All libraries"}
+	selected := 0
+	for i, folder := range folders {
+		options = append(options, folder.Name)
+		if strconv.Itoa(folder.ID) == current {
+			selected = i + 1
+		}
+	}
+	return options, selected
+}
+
+// setMusicFolder 切换音乐库并按当前混音条件重新加载歌曲
+func (a *Application) setMusicFolder(id string) {
+	if id == a.subsonicClient.MusicFolder() {
+		return
+	}
+	a.subsonicClient.SetMusicFolder(id)
+
+	go func() {
+		if err := a.loadMusic(); err != nil {
+			a.application.QueueUpdateDraw(func() {
+				a.statusBar.SetText("[red]load music failed: " + err.Error())
+			})
+		}
+	}()
+}
+
+// showMusicFolders 打开音乐库选择列表
+func (a *Application) showMusicFolders() {
+	go func() {
+		folders, err := a.subsonicClient.GetMusicFoldersContext(a.ctx)
+		if err != nil {
+			a.application.QueueUpdateDraw(func() {
+				a.statusBar.SetText("[red]load music folders failed: " + err.Error())
+			})
+			return
+		}
+
+		a.application.QueueUpdateDraw(func() {
+			options, selected := a.musicFolderOptions(folders)
+
+			list := tview.NewList().ShowSecondaryText(false)
+			list.SetBorder(true).SetTitle(" Music Folders ")
+			for _, option := range options {
+				list.AddItem(tview.Escape(option), "", 0, nil)
+			}
+			list.SetCurrentItem(selected)
+			list.SetSelectedFunc(func(index int, _, _ string, _ rune) {
+				id := ""
+				if index > 0 {
+					id = strconv.Itoa(folders[index-1].ID)
+				}
+				a.closeOverlay()
+				a.setMusicFolder(id)
+			})
+
+			a.showOverlay("folders", list, 40, min(len(options)+2, 20))
+		})
+	}()
+}
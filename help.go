@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+var helpSections = []struct {
+	context string
+	title   string
+}{
+	{contextGlobal, "Global"},
+	{contextTable, "Song list"},
+}
+
+// windowKeys 悬浮窗口和输入框内的按键，由各窗口自己处理，不在动作表中，不能通过配置修改
+var windowKeys = []struct {
+	title string
+	keys  [][2]string
+}{
+	{"Play queue (Q)", [][2]string{
+		{"Enter", "Play the selected track now"},
+		{"x", "Remove the selected track"},
+		{"c", "Clear the queue"},
+	}},
+	{"Filter and command line (/ and :)", [][2]string{
+		{"Enter", "Keep the filter / run the command"},
+		{"Esc", "Restore the full list / close"},
+		{"Tab", "Complete the command line"},
+		{"Up Down", "Command history"},
+	}},
+}
+
+// showHelp 按上下文分组显示所有按键和命令，内容来自动作表，配置覆盖的按键也会反映出来
+func (a *Application) showHelp() {
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(false)
+	view.SetBorder(true).SetTitle(" Help (ESC to close) ")
+	view.SetText(a.renderHelp())
+	a.showOverlay("help", view, 100, 36)
+}
+
+func (a *Application) renderHelp() string {
+	var b strings.Builder
+	for i, section := range helpSections {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "[yellow]%s\n", section.title)
+		for _, act := range a.actions() {
+			if act.Context != section.context {
+				continue
+			}
+			command := ":" + act.Name
+			if act.Usage != "" {
+				command += " " + act.Usage
+			}
+			fmt.Fprintf(&b, "[lightgreen]%-18s [-]%-50s [darkgray]%s\n",
+				tview.Escape(strings.Join(act.Keys, " ")), act.Help, tview.Escape(command))
+		}
+	}
+	for _, section := range windowKeys {
+		fmt.Fprintf(&b, "\n[yellow]%s\n", section.title)
+		for _, key := range section.keys {
+			fmt.Fprintf(&b, "[lightgreen]%-18s [-]%s\n", key[0], key[1])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,98 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Play 一次播放记录
+type Play struct {
+	SongID    string    `json:"songId"`
+	Title     string    `json:"title"`
+	Artist    string    `json:"artist"`
+	Album     string    `json:"album"`
+	StartedAt time.Time `json:"startedAt"`
+	Listened  int       `json:"listened"` // 实际收听秒数
+	Duration  int       `json:"duration"`
+	Completed bool      `json:"completed"`
+	Skipped   bool      `json:"skipped"`
+}
+
+// Store 本地播放历史，以 JSON Lines 追加写入文件
+type Store struct {
+	path  string
+	mu    sync.Mutex
+	plays []Play
+}
+
+func Open(path string) (*Store, error) {
+	s := &Store{path: path}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create history dir failed: %w", err)
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open history failed: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var p Play
+		// 跳过损坏的行，不影响其余记录
+		if err := json.Unmarshal(scanner.Bytes(), &p); err != nil {
+			continue
+		}
+		s.plays = append(s.plays, p)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read history failed: %w", err)
+	}
+	return s, nil
+}
+
+func (s *Store) Add(p Play) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open history failed: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("write history failed: %w", err)
+	}
+	s.plays = append(s.plays, p)
+	return nil
+}
+
+// Plays 返回 since 之后开始的播放记录
+func (s *Store) Plays(since time.Time) []Play {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]Play, 0)
+	for _, p := range s.plays {
+		if !p.StartedAt.Before(since) {
+			result = append(result, p)
+		}
+	}
+	return result
+}
@@ -0,0 +1,118 @@
+package history
+
+import (
+	"sort"
+	"time"
+)
+
+type Period int
+
+const (
+	PeriodWeek Period = iota
+	PeriodMonth
+	PeriodYear
+)
+
+func (p Period) String() string {
+	switch p {
+	case PeriodWeek:
+		return "This week"
+	case PeriodMonth:
+		return "This month"
+	case PeriodYear:
+		return "This year"
+	}
+	return "Unknown"
+}
+
+// Since 返回统计周期的起始时间：本周一、本月一日或今年一月一日的零点（now 所在时区）
+func (p Period) Since(now time.Time) time.Time {
+	year, month, day := now.Date()
+	switch p {
+	case PeriodMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, now.Location())
+	case PeriodYear:
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, now.Location())
+	default:
+		// 一周从周一开始
+		offset := (int(now.Weekday()) + 6) % 7
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, now.Location())
+	}
+}
+
+type Count struct {
+	Name     string
+	Plays    int
+	Listened int
+}
+
+type Stats struct {
+	Period     Period
+	Plays      int
+	Skips      int
+	Listened   time.Duration
+	TopArtists []Count
+	TopAlbums  []Count
+	TopTracks  []Count
+}
+
+func (s Stats) SkipRate() float64 {
+	if s.Plays == 0 {
+		return 0
+	}
+	return float64(s.Skips) / float64(s.Plays)
+}
+
+func (s *Store) Stats(period Period, now time.Time, limit int) Stats {
+	plays := s.Plays(period.Since(now))
+
+	stats := Stats{Period: period, Plays: len(plays)}
+	artists := make(map[string]*Count)
+	albums := make(map[string]*Count)
+	tracks := make(map[string]*Count)
+
+	for _, p := range plays {
+		stats.Listened += time.Duration(p.Listened) * time.Second
+		if p.Skipped {
+			stats.Skips++
+		}
+		addCount(artists, p.Artist, p.Listened)
+		addCount(albums, p.Album, p.Listened)
+		addCount(tracks, p.Title+" - "+p.Artist, p.Listened)
+	}
+
+	stats.TopArtists = topCounts(artists, limit)
+	stats.TopAlbums = topCounts(albums, limit)
+	stats.TopTracks = topCounts(tracks, limit)
+	return stats
+}
+
+func addCount(m map[string]*Count, name string, listened int) {
+	if name == "" {
+		return
+	}
+	c, ok := m[name]
+	if !ok {
+		c = &Count{Name: name}
+		m[name] = c
+	}
+	c.Plays++
+	c.Listened += listened
+}
+
+func topCounts(m map[string]*Count, limit int) []Count {
+	result := make([]Count, 0, len(m))
+	for _, c := range m {
+		result = append(result, *c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Plays != result[j].Plays {
+			return result[i].Plays > result[j].Plays
+		}
+		return result[i].Name < result[j].Name
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
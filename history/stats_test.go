@@ -0,0 +1,78 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPeriodSince(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*60*60)
+	tests := []struct {
+		period Period
+		now    time.Time
+		want   time.Time
+	}{
+		// 2026-10-18 是周日，本周从 10-12 周一开始
+		{PeriodWeek, time.Date(2026, 10, 18, 23, 59, 0, 0, loc), time.Date(2026, 10, 12, 0, 0, 0, 0, loc)},
+		{PeriodWeek, time.Date(2026, 10, 12, 0, 0, 0, 0, loc), time.Date(2026, 10, 12, 0, 0, 0, 0, loc)},
+		// 跨月的一周
+		{PeriodWeek, time.Date(2026, 10, 1, 8, 0, 0, 0, loc), time.Date(2026, 9, 28, 0, 0, 0, 0, loc)},
+		// 跨年的一周
+		{PeriodWeek, time.Date(2027, 1, 1, 8, 0, 0, 0, loc), time.Date(2026, 12, 28, 0, 0, 0, 0, loc)},
+		{PeriodMonth, time.Date(2026, 3, 31, 12, 0, 0, 0, loc), time.Date(2026, 3, 1, 0, 0, 0, 0, loc)},
+		{PeriodYear, time.Date(2026, 10, 18, 12, 0, 0, 0, loc), time.Date(2026, 1, 1, 0, 0, 0, 0, loc)},
+	}
+	for _, tt := range tests {
+		if got := tt.period.Since(tt.now); !got.Equal(tt.want) {
+			t.Errorf("%s.Since(%s) = %s, want %s", tt.period, tt.now, got, tt.want)
+		}
+	}
+}
+
+func TestStats(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "history.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	plays := []Play{
+		{Title: "A", Artist: "X", Album: "One", StartedAt: now.Add(-time.Hour), Listened: 200, Completed: true},
+		{Title: "A", Artist: "X", Album: "One", StartedAt: now.Add(-2 * time.Hour), Listened: 30, Skipped: true},
+		{Title: "B", Artist: "Y", Album: "Two", StartedAt: now.Add(-3 * time.Hour), Listened: 180, Completed: true},
+		{Title: "C", Artist: "Y", Album: "Two", StartedAt: now.Add(-4 * time.Hour), Listened: 100},
+		// 上周的记录不计入本周
+		{Title: "D", Artist: "Z", Album: "Three", StartedAt: now.AddDate(0, 0, -7), Listened: 100},
+	}
+	for _, p := range plays {
+		if err := s.Add(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats := s.Stats(PeriodWeek, now, 1)
+	if stats.Plays != 4 || stats.Skips != 1 {
+		t.Errorf("plays, skips = %d, %d; want 4, 1", stats.Plays, stats.Skips)
+	}
+	if stats.Listened != 510*time.Second {
+		t.Errorf("listened = %s, want 8m30s", stats.Listened)
+	}
+	if stats.SkipRate() != 0.25 {
+		t.Errorf("skip rate = %g, want 0.25", stats.SkipRate())
+	}
+	// 播放次数相同时按名称排序
+	if len(stats.TopArtists) != 1 || stats.TopArtists[0].Name != "X" {
+		t.Errorf("top artists = %v, want [X]", stats.TopArtists)
+	}
+	if len(stats.TopTracks) != 1 || stats.TopTracks[0] != (Count{Name: "A - X", Plays: 2, Listened: 230}) {
+		t.Errorf("top tracks = %v", stats.TopTracks)
+	}
+
+	if got := s.Stats(PeriodMonth, now, 0).Plays; got != 5 {
+		t.Errorf("month plays = %d, want 5", got)
+	}
+	if (Stats{}).SkipRate() != 0 {
+		t.Error("skip rate without plays should be 0")
+	}
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/wildeyedskies/go-mpv/mpv"
+	"github.com/yhkl-dev/NaviCLI/subsonic"
+)
+
+// namedLoop 保存的 A-B 循环，A 和 B 为歌曲中的绝对秒数
+type namedLoop struct {
+	Name string  `json:"name"`
+	A    float64 `json:"a"`
+	B    float64 `json:"b"`
+}
+
+// loadLoops 读取 loops.json：歌曲 ID 到保存的循环
+func loadLoops() (map[string][]namedLoop, error) {
+	loops := make(map[string][]namedLoop)
+	data, err := os.ReadFile(dataPath("loops.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return loops, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &loops); err != nil {
+		return nil, err
+	}
+	return loops, nil
+}
+
+func saveLoops(loops map[string][]namedLoop) error {
+	data, err := json.MarshalIndent(loops, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := dataPath("loops.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// setLoop 设置当前歌曲的循环点（绝对秒数，负数为未设置）。
+// 服务器端定位的流从 playOffset 开始，mpv 的循环点需要减去偏移
+func (a *Application) setLoop(loopA, loopB float64) error {
+	if a.mpvInstance == nil || a.mpvInstance.Mpv == nil {
+		return errors.New("player is not available")
+	}
+	a.loadingMux.Lock()
+	offset := a.playOffset
+	a.loopA, a.loopB = loopA, loopB
+	a.loadingMux.Unlock()
+
+	relative := func(seconds float64) float64 {
+		if seconds < 0 {
+			return -1
+		}
+		return max(seconds-offset, 0)
+	}
+	return a.mpvInstance.SetABLoop(relative(loopA), relative(loopB))
+}
+
+func (a *Application) loopPoints() (float64, float64) {
+	a.loadingMux.Lock()
+	defer a.loadingMux.Unlock()
+	return a.loopA, a.loopB
+}
+
+// loopCommand 无参数时依次设置 A 点、B 点、清除循环；也可以 a/b <位置>、off、save <名称>、load <名称>
+func (a *Application) loopCommand(arg string) error {
+	a.loadingMux.Lock()
+	song := a.currentSong
+	station := a.currentStation
+	position := a.playPosition
+	offset := a.playOffset
+	a.loadingMux.Unlock()
+	if song == nil || station != nil {
+		return errors.New("no track is playing")
+	}
+	// 进度条每秒才更新一次播放位置，循环点直接读取 mpv 的当前位置
+	if pos, err := a.mpvInstance.GetProperty("time-pos", mpv.FORMAT_DOUBLE); err == nil {
+		position = pos.(float64) + offset
+	}
+
+	loopA, loopB := a.loopPoints()
+	sub, value, _ := strings.Cut(arg, " ")
+	value = strings.Trim(strings.TrimSpace(value), `"`)
+
+	switch sub {
+	case "":
+		switch {
+		case loopA < 0:
+			loopA = position
+		case loopB < 0:
+			loopB = position
+		default:
+			loopA, loopB = -1, -1
+		}
+	case "a", "b":
+		at := position
+		if value != "" {
+			seconds, err := parsePosition(value)
+			if err != nil {
+				return err
+			}
+			at = seconds
+		}
+		if sub == "a" {
+			loopA = at
+		} else {
+			loopB = at
+		}
+	case "off":
+		loopA, loopB = -1, -1
+	case "save":
+		return a.saveLoop(*song, value, loopA, loopB)
+	case "load":
+		loop, err := a.findLoop(song.ID, value)
+		if err != nil {
+			return err
+		}
+		loopA, loopB = loop.A, loop.B
+	case "delete":
+		return a.deleteLoop(song.ID, value)
+	default:
+		return fmt.Errorf("unknown loop command: %s", sub)
+	}
+
+	if loopA >= 0 && loopB >= 0 && loopB <= loopA {
+		return errors.New("loop B must be after A")
+	}
+	if err := a.setLoop(loopA, loopB); err != nil {
+		return err
+	}
+	a.setMessage("[lightgreen]" + loopText(loopA, loopB))
+	return nil
+}
+
+// loopText 当前循环的描述，如 "A-B 01:20-01:45"
+func loopText(loopA, loopB float64) string {
+	point := func(seconds float64) string {
+		if seconds < 0 {
+			return "--:--"
+		}
+		return formatDuration(int(seconds))
+	}
+	if loopA < 0 && loopB < 0 {
+		return "loop off"
+	}
+	return "A-B " + point(loopA) + "-" + point(loopB)
+}
+
+func (a *Application) saveLoop(song subsonic.Song, name string, loopA, loopB float64) error {
+	if name == "" {
+		return errors.New(`usage: loop save "<name>"`)
+	}
+	if loopA < 0 || loopB < 0 {
+		return errors.New("set both A and B before saving")
+	}
+	loops, err := loadLoops()
+	if err != nil {
+		return err
+	}
+	saved := loops[song.ID][:0:0]
+	for _, loop := range loops[song.ID] {
+		if !strings.EqualFold(loop.Name, name) {
+			saved = append(saved, loop)
+		}
+	}
+	saved = append(saved, namedLoop{Name: name, A: loopA, B: loopB})
+	sort.Slice(saved, func(i, j int) bool { return saved[i].A < saved[j].A })
+	loops[song.ID] = saved
+	if err := saveLoops(loops); err != nil {
+		return err
+	}
+	a.setMessage(fmt.Sprintf("[lightgreen]loop %s saved for %s", name, song.Title))
+	return nil
+}
+
+func (a *Application) findLoop(songID, name string) (namedLoop, error) {
+	loops, err := loadLoops()
+	if err != nil {
+		return namedLoop{}, err
+	}
+	for _, loop := range loops[songID] {
+		if strings.EqualFold(loop.Name, name) {
+			return loop, nil
+		}
+	}
+	return namedLoop{}, fmt.Errorf("no loop named %q for this track", name)
+}
+
+func (a *Application) deleteLoop(songID, name string) error {
+	loops, err := loadLoops()
+	if err != nil {
+		return err
+	}
+	var kept []namedLoop
+	for _, loop := range loops[songID] {
+		if !strings.EqualFold(loop.Name, name) {
+			kept = append(kept, loop)
+		}
+	}
+	if len(kept) == len(loops[songID]) {
+		return fmt.Errorf("no loop named %q for this track", name)
+	}
+	if len(kept) == 0 {
+		delete(loops, songID)
+	} else {
+		loops[songID] = kept
+	}
+	if err := saveLoops(loops); err != nil {
+		return err
+	}
+	a.setMessage("[lightgreen]loop " + name + " deleted")
+	return nil
+}
+
+// songLoopNames 当前歌曲保存的循环名称，用于命令行补全
+func (a *Application) songLoopNames() []string {
+	a.loadingMux.Lock()
+	song := a.currentSong
+	a.loadingMux.Unlock()
+	if song == nil {
+		return nil
+	}
+	loops, err := loadLoops()
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, loop := range loops[song.ID] {
+		name := loop.Name
+		if strings.ContainsAny(name, " \t") {
+			name = `"` + name + `"`
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// showLoops 当前歌曲保存的循环，Enter 开始循环，x 删除
+func (a *Application) showLoops() {
+	a.loadingMux.Lock()
+	song := a.currentSong
+	a.loadingMux.Unlock()
+	if song == nil {
+		return
+	}
+	loops, err := loadLoops()
+	if err != nil {
+		a.setMessage("[red]load loops failed: " + err.Error())
+		return
+	}
+	saved := loops[song.ID]
+	if len(saved) == 0 {
+		a.setMessage(`[yellow]no saved loops, set A and B with l then :loop save "<name>"`)
+		return
+	}
+
+	list := tview.NewList().ShowSecondaryText(false)
+	list.SetBorder(true).SetTitle(" Loops: " + tview.Escape(song.Title) + " (x to delete) ")
+	for _, loop := range saved {
+		list.AddItem(fmt.Sprintf("%s [darkgray]%s", tview.Escape(loop.Name), loopText(loop.A, loop.B)), "", 0, nil)
+	}
+	list.SetSelectedFunc(func(index int, _, _ string, _ rune) {
+		a.closeOverlay()
+		if err := a.loopCommand(`load "` + saved[index].Name + `"`); err != nil {
+			a.setMessage("[red]" + err.Error())
+		}
+	})
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Rune() == 'x' {
+			index := list.GetCurrentItem()
+			a.closeOverlay()
+			if err := a.deleteLoop(song.ID, saved[index].Name); err != nil {
+				a.setMessage("[red]" + err.Error())
+			}
+			return nil
+		}
+		return event
+	})
+
+	a.showOverlay("loops", list, 60, min(len(saved)+2, 20))
+}
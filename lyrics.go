@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+	"github.com/yhkl-dev/NaviCLI/subsonic"
+)
+
+// showLyrics 显示当前歌曲歌词，同步歌词会随播放进度高亮当前行
+func (a *Application) showLyrics() {
+	a.loadingMux.Lock()
+	song := a.currentSong
+	a.loadingMux.Unlock()
+	if song == nil {
+		return
+	}
+
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(true)
+	view.SetBorder(true).SetTitle(fmt.Sprintf(" %s - %s ", tview.Escape(song.Title), tview.Escape(song.Artist)))
+	view.SetText("[darkgray]Loading lyrics...")
+	a.showOverlay("lyrics", view, 70, 30)
+
+	go func() {
+		lyrics, err := a.subsonicClient.GetLyricsContext(a.ctx, *song)
+		if err != nil {
+			text := "[red]load lyrics failed: " + tview.Escape(err.Error())
+			if errors.Is(err, subsonic.ErrNotFound) {
+				text = "[darkgray]No lyrics found"
+			}
+			a.application.QueueUpdateDraw(func() {
+				view.SetText(text)
+			})
+			return
+		}
+
+		a.application.QueueUpdateDraw(func() {
+			view.SetText(renderLyrics(lyrics, -1))
+		})
+		if !lyrics.Synced {
+			return
+		}
+
+		ticker := time.NewTicker(500 * time.Millisecond)
+		defer ticker.Stop()
+		for range ticker.C {
+			a.searchMux.Lock()
+			open := a.overlay == "lyrics"
+			a.searchMux.Unlock()
+
+			a.loadingMux.Lock()
+			sameSong := a.currentSong != nil && a.currentSong.ID == song.ID
+			position := a.playPosition
+			a.loadingMux.Unlock()
+
+			if !open || !sameSong {
+				return
+			}
+
+			current := currentLyricLine(lyrics, position)
+			a.application.QueueUpdateDraw(func() {
+				view.SetText(renderLyrics(lyrics, current))
+				view.ScrollTo(max(current-5, 0), 0)
+			})
+		}
+	}()
+}
+
+// currentLyricLine 返回 position 秒时应高亮的行号
+func currentLyricLine(lyrics *subsonic.StructuredLyrics, position float64) int {
+	ms := int64(position*1000) + lyrics.Offset
+	current := -1
+	for i, line := range lyrics.Lines {
+		if line.Start > ms {
+			break
+		}
+		current = i
+	}
+	return current
+}
+
+func renderLyrics(lyrics *subsonic.StructuredLyrics, current int) string {
+	var b strings.Builder
+	for i, line := range lyrics.Lines {
+		color := "gray"
+		if i == current {
+			color = "lightgreen"
+		}
+		fmt.Fprintf(&b, "[%s]%s\n", color, tview.Escape(line.Value))
+	}
+	return b.String()
+}
@@ -2,10 +2,12 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"sync"
@@ -16,7 +18,9 @@ import (
 	"github.com/rivo/tview"
 	"github.com/spf13/viper"
 	"github.com/wildeyedskies/go-mpv/mpv"
+	"github.com/yhkl-dev/NaviCLI/history"
 	"github.com/yhkl-dev/NaviCLI/mpvplayer"
+	"github.com/yhkl-dev/NaviCLI/streamproxy"
 	"github.com/yhkl-dev/NaviCLI/subsonic"
 )
 
@@ -27,16 +31,63 @@ func formatDuration(seconds int) string {
 }
 
 type Application struct {
+	ctx            context.Context
 	application    *tview.Application
 	subsonicClient *subsonic.Client
+	streamProxy    *streamproxy.Server
 	mpvInstance    *mpvplayer.Mpvplayer
-	totalSongs     []subsonic.Song
+	totalSongs     []subsonic.Song // 排序后显示的列表
+	baseSongs      []subsonic.Song // 按加载顺序的列表
 	currentPage    int
 	pageSize       int
 	totalPages     int
 
+	// 当前视图（mix、search、album 等），每个视图有自己的列和排序
+	view    string
+	layouts map[string]*tableLayout
+
+	playlistNames []string
+
+	// 实时过滤，Esc 恢复完整列表和 filterRestore 对应的选中歌曲
+	filter        string
+	activeFilter  songFilter
+	filterRestore string
+
+	// 表格下方的输入框（过滤、命令行）
+	promptInput  *tview.InputField
+	promptHint   *tview.TextView
+	promptCancel func()
+
+	actionList     []*action
+	commandHistory []string
+	library        libraryIndex
+	theme          string
+	serverPrefix   string // 当前服务器的配置前缀：server 或 servers.<name>
+	effects        mpvplayer.Effects
+	replayGain     mpvplayer.ReplayGain
+	gainInfo       string // 当前歌曲实际应用的增益，播放面板显示
+	speed          float64
+	sleep          *sleepTimer
+	crossfader     *mpvplayer.Crossfader
+	loopA, loopB   float64 // 当前歌曲的 A-B 循环点（绝对秒数），-1 为未设置
+	alarm          *alarm
+
+	// 多选标记和播放队列
+	marked     map[string]bool
+	markAnchor int
+	queue      []subsonic.Song
+	message    string
+
+	// 服务器端分页列表，翻到最后一页时继续获取
+	source       songSource
+	sourceCtx    context.Context
+	sourceDone   bool
+	fetchingMore bool
+
 	rootFlex    *tview.Flex
 	songTable   *tview.Table
+	rightPanel  *tview.Flex
+	pageBar     *tview.TextView
 	statusBar   *tview.TextView
 	progressBar *tview.TextView
 	statsBar    *tview.TextView
@@ -45,62 +96,86 @@ type Application struct {
 	isLoading   bool
 	loadingMux  sync.Mutex
 
+	// 加载歌曲和刷新列表时取消上一次未完成的请求
+	loadCancel    context.CancelFunc
+	loadSeq       int
+	refreshCancel context.CancelFunc
+
 	currentSongIndex int
-	isSearching      bool
 	searchMux        sync.Mutex
+	overlay          string
+	overlayClosed    func() // 悬浮窗口关闭时调用，用于面板在关闭时保存设置
+
+	history       *history.Store
+	recordWG      sync.WaitGroup // 尚未完成的书签和历史写入，退出前等待
+	playStartedAt time.Time
+	playPosition  float64
+	playOffset    float64 // 服务器从该位置开始转码时 mpv 的 time-pos 需要加上的偏移
+
+	radioMode     bool
+	radioFetching chan struct{} // 正在补充电台队列时非 nil，补充完成时关闭
+
+	currentStation *subsonic.InternetRadioStation
+
+	mix mixOptions
+}
+
+// dataPath 返回本地数据文件路径
+func dataPath(name string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = "."
+	}
+	return filepath.Join(home, ".config", "navicli", name)
 }
 
 func (a *Application) setupPagination() {
-	a.pageSize = 500
+	a.pageSize = max(viper.GetInt("ui.page_size"), 1)
+	a.view = "mix"
+	a.markAnchor = -1
 	a.currentPage = 1
 	a.currentSongIndex = -1
 	a.isLoading = false
+	a.loopA, a.loopB = -1, -1
 }
 
 func (a *Application) playSongAtIndex(index int) {
 	if index < 0 || index >= len(a.totalSongs) {
 		return
 	}
+	a.playSong(a.totalSongs[index], index)
+}
+
+// playSong 播放歌曲，index 为歌曲在列表中的位置。
+// 播放队列中不在列表里的歌曲时传入原来的位置，队列播完后从列表继续
+func (a *Application) playSong(currentTrack subsonic.Song, index int) {
+	a.cancelCrossfade(currentTrack.ID)
+	a.recordPlay(playSkipped)
 
 	a.loadingMux.Lock()
-	if a.isLoading {
-		a.loadingMux.Unlock()
-		return
+	// 用户选择了其他歌曲，取消上一次尚未完成的加载
+	if a.loadCancel != nil {
+		a.loadCancel()
 	}
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.loadCancel = cancel
+	a.loadSeq++
+	seq := a.loadSeq
 	a.isLoading = true
 	a.currentSongIndex = index
-	currentTrack := a.totalSongs[index]
 	a.currentSong = &currentTrack
+	a.currentStation = nil
 	a.isPlaying = false
 	a.loadingMux.Unlock()
 
 	// 更新表格选中行
 	a.application.QueueUpdateDraw(func() {
-		a.songTable.Select(index+1, 0)
-		// 确保选中的行在可视区域内
-		a.songTable.ScrollToBeginning()
+		if index >= 0 && index < len(a.totalSongs) && a.totalSongs[index].ID == currentTrack.ID {
+			a.showSong(index)
+		}
 	})
 
-	loadingBar := "[yellow]▓▓▓[darkgray]░░░░░░░░░░░░░░░░░░░░░░░░░░░░░ Loading..."
-	info := fmt.Sprintf(`
-[white]Current %d:
-[yellow]%s [darkgray](Loading...)
-
-[darkgray][play] %s
-[darkgray][source] %.1f MB
-[darkgray][favourite]
-
-[gray]%s - %s
-[gray]%s
-%s`,
-		index+1,
-		currentTrack.Title,
-		formatDuration(currentTrack.Duration),
-		float64(currentTrack.Size)/1024/1024,
-		currentTrack.Artist,
-		currentTrack.Album,
-		currentTrack.Album,
-		loadingBar)
+	info := a.loadingInfo(index, currentTrack)
 
 	a.application.QueueUpdateDraw(func() {
 		if a.statusBar != nil {
@@ -111,8 +186,12 @@ func (a *Application) playSongAtIndex(index int) {
 	go func() {
 		defer func() {
 			a.loadingMux.Lock()
-			a.isLoading = false
+			if a.loadSeq == seq {
+				a.isLoading = false
+				a.loadCancel = nil
+			}
 			a.loadingMux.Unlock()
+			cancel()
 
 			if r := recover(); r != nil {
 
@@ -120,7 +199,7 @@ func (a *Application) playSongAtIndex(index int) {
 				a.application.QueueUpdateDraw(func() {
 					if a.statusBar != nil {
 						failedInfo := fmt.Sprintf(`
-[white]Current %d:
+[-]Current %d:
 [red]%s [darkgray](Failed)
 
 [darkgray][play] %s
@@ -143,29 +222,15 @@ func (a *Application) playSongAtIndex(index int) {
 			}
 		}()
 
-		done := make(chan string, 1)
-		go func() {
-			defer func() {
-				if r := recover(); r != nil {
-
-					done <- ""
-				}
-			}()
-			url := a.subsonicClient.GetPlayURL(currentTrack.ID)
-			done <- url
-		}()
-
-		var playURL string
-		select {
-		case playURL = <-done:
-			if playURL == "" {
-
-				return
-			}
-		case <-time.After(10 * time.Second):
-
+		start := a.resumePosition(ctx, currentTrack)
+		playURL, serverSeek := a.streamURL(currentTrack.ID, start)
+		if ctx.Err() != nil {
 			return
 		}
+		mpvStart, offset := start, 0
+		if serverSeek {
+			mpvStart, offset = 0, start
+		}
 
 		if a.mpvInstance != nil {
 			a.mpvInstance.Queue = []mpvplayer.QueueItem{{
@@ -180,15 +245,25 @@ func (a *Application) playSongAtIndex(index int) {
 				a.mpvInstance.Stop()
 				time.Sleep(50 * time.Millisecond)
 			}
+			if ctx.Err() != nil {
+				return
+			}
 
 			if a.mpvInstance.Mpv != nil {
-				a.mpvInstance.Play(playURL)
+				a.setSpeed(defaultSpeed(currentTrack))
+				a.setLoop(-1, -1)
+				a.mpvInstance.PlayFrom(playURL, mpvStart)
 
 				a.isPlaying = true
+				a.loadingMux.Lock()
+				a.playStartedAt = time.Now()
+				a.playPosition = float64(start)
+				a.playOffset = float64(offset)
+				a.loadingMux.Unlock()
 
 				playingBar := "[lightgreen]▓[darkgray]░░░░░░░░░░░░░░░░░░░░░░░░░░░░░ 0.0%"
 				playingInfo := fmt.Sprintf(`
-[white]Current %d:
+[-]Current %d:
 [lightgreen]%s
 
 [darkgray][play] %s
@@ -219,6 +294,69 @@ func (a *Application) playSongAtIndex(index int) {
 	}()
 }
 
+// streamURL 优先返回本地代理地址，使 mpv 看不到认证参数
+func (a *Application) streamURL(songID string, offset int) (string, bool) {
+	if a.streamProxy != nil {
+		return a.streamProxy.URL(songID, offset)
+	}
+	return a.subsonicClient.GetPlayURLAt(songID, offset)
+}
+
+// playEnd 一次播放结束的原因
+type playEnd int
+
+const (
+	playStopped   playEnd = iota // 退出程序、切换服务器或电台，不算跳过
+	playSkipped                  // 用户切到了其他歌曲
+	playCompleted                // 播放到结尾
+)
+
+// recordPlay 将当前歌曲的收听情况写入播放历史并更新书签。
+// 书签需要访问服务器，在后台写入，不阻塞 mpv 事件循环和界面
+func (a *Application) recordPlay(end playEnd) {
+	a.loadingMux.Lock()
+	song := a.currentSong
+	startedAt := a.playStartedAt
+	listened := int(a.playPosition)
+	a.playStartedAt = time.Time{}
+	a.playPosition = 0
+	a.loadingMux.Unlock()
+
+	if song == nil || startedAt.IsZero() {
+		return
+	}
+	// 切换服务器时书签仍写回原来的服务器
+	client, current := a.subsonicClient, *song
+	completed := end == playCompleted
+	play := history.Play{
+		SongID:    song.ID,
+		Title:     song.Title,
+		Artist:    song.Artist,
+		Album:     song.Album,
+		StartedAt: startedAt,
+		Listened:  listened,
+		Duration:  song.Duration,
+		Completed: completed,
+		Skipped:   end == playSkipped,
+	}
+	if completed {
+		play.Listened = song.Duration
+	}
+
+	a.recordWG.Add(1)
+	go func() {
+		defer a.recordWG.Done()
+		a.updateBookmark(client, current, listened, completed)
+
+		if a.history == nil {
+			return
+		}
+		if err := a.history.Add(play); err != nil {
+			log.Println("record play failed:", err)
+		}
+	}()
+}
+
 func (a *Application) playNextSong() {
 	if len(a.totalSongs) == 0 {
 		return
@@ -233,7 +371,48 @@ func (a *Application) playNextSong() {
 		return
 	}
 
+	// 优先播放队列中的歌曲
+	if song, ok := a.popQueue(); ok {
+		index := a.indexOf(song.ID)
+		if index < 0 {
+			index = a.currentSongIndex
+		}
+		go a.playSong(song, index)
+		return
+	}
+
 	nextIndex := a.currentSongIndex + 1
+
+	// 电台模式下队列快播完时自动补充，不回到开头
+	if a.isRadioMode() && len(a.totalSongs)-nextIndex <= radioLowWater {
+		if nextIndex >= len(a.totalSongs) {
+			go func() {
+				err := a.topUpRadio()
+				if err != nil {
+					log.Println("top up radio failed:", err)
+				}
+				var added bool
+				a.syncUpdate(func() {
+					added = nextIndex < len(a.totalSongs)
+				})
+				switch {
+				case added:
+					a.playSongAtIndex(nextIndex)
+				case err != nil:
+					a.setMessage("[red]radio stopped, top up failed: " + err.Error())
+				default:
+					a.setMessage("[yellow]radio stopped: no more songs to add")
+				}
+			}()
+			return
+		}
+		go func() {
+			if err := a.topUpRadio(); err != nil {
+				log.Println("top up radio failed:", err)
+			}
+		}()
+	}
+
 	if nextIndex >= len(a.totalSongs) {
 		nextIndex = 0
 	}
@@ -285,10 +464,20 @@ func (a *Application) updateProgressBar() {
 			currentSongPtr := a.currentSong
 			currentIndex := a.currentSongIndex
 			isCurrentlyPlaying := a.isPlaying
+			currentStationPtr := a.currentStation
+			playOffset := a.playOffset
 			a.loadingMux.Unlock()
 
+			// 加载中显示代理的预读进度
 			if isCurrentlyLoading {
-
+				if currentSongPtr != nil && currentStationPtr == nil {
+					info := a.loadingInfo(currentIndex, *currentSongPtr)
+					a.application.QueueUpdateDraw(func() {
+						if a.statusBar != nil {
+							a.statusBar.SetText(info)
+						}
+					})
+				}
 				continue
 			}
 
@@ -307,6 +496,14 @@ func (a *Application) updateProgressBar() {
 			}
 
 			if !isCurrentlyPlaying {
+				// 还没有播放过歌曲时只显示闹钟倒计时
+				if timers := a.sleepDisplay(); currentSongPtr == nil && timers != "" {
+					a.application.QueueUpdateDraw(func() {
+						if a.progressBar != nil {
+							a.progressBar.SetText("\n[darkgray]--:--/--:--" + timers)
+						}
+					})
+				}
 				if currentSongPtr != nil {
 					// 获取音量信息用于暂停状态显示
 					volumeDisplay := "100%"
@@ -322,12 +519,12 @@ func (a *Application) updateProgressBar() {
 					a.application.QueueUpdateDraw(func() {
 						if a.progressBar != nil && a.statusBar != nil {
 							pausedDisplay := fmt.Sprintf(`
-[darkgray]00:00:00 [darkgray][v-] [darkgray]%s [darkgray][v+] [darkgray][random]`, volumeDisplay)
+[darkgray]00:00:00 [darkgray][v-] [darkgray]%s [darkgray][v+] [darkgray][random]%s`, volumeDisplay, a.sleepDisplay())
 							a.progressBar.SetText(pausedDisplay)
 
 							progressBar := "[darkgray]▓▓▓▓▓▓▓▓░░░░░░░░░░░░░░░░░░░░░░ 0%"
 							statusInfo := fmt.Sprintf(`
-[white]Episode %d:
+[-]Episode %d:
 [yellow]%s [darkgray](PAUSED)
 
 [darkgray][play] %s
@@ -367,6 +564,7 @@ func (a *Application) updateProgressBar() {
 				var volume float64 = 100
 				var isMuted = false
 				var hasError bool
+				var streamTitle string
 
 				go func() {
 					defer func() {
@@ -381,10 +579,14 @@ func (a *Application) updateProgressBar() {
 						hasError = true
 						return
 					}
-					duration, err := a.mpvInstance.GetProperty("duration", mpv.FORMAT_DOUBLE)
-					if err != nil {
+					// 网络电台没有时长，显示 ICY 元数据中的曲目
+					if currentStationPtr != nil {
+						streamTitle = a.mpvInstance.GetStreamTitle()
+					} else if duration, err := a.mpvInstance.GetProperty("duration", mpv.FORMAT_DOUBLE); err != nil {
 						hasError = true
 						return
+					} else {
+						totalDuration = duration.(float64) + playOffset
 					}
 
 					// 获取音量和静音状态
@@ -395,8 +597,7 @@ func (a *Application) updateProgressBar() {
 						isMuted = mute.(bool)
 					}
 
-					currentPos = pos.(float64)
-					totalDuration = duration.(float64)
+					currentPos = pos.(float64) + playOffset
 				}()
 
 				select {
@@ -408,32 +609,47 @@ func (a *Application) updateProgressBar() {
 					return
 				}
 
-				if totalDuration <= 0 || currentPos < 0 {
+				if currentPos < 0 || (totalDuration <= 0 && currentStationPtr == nil) {
 					return
 				}
 
+				a.loadingMux.Lock()
+				a.playPosition = currentPos
+				a.loadingMux.Unlock()
+
 				currentTime := formatDuration(int(currentPos))
-				totalTime := formatDuration(int(totalDuration))
+				totalTime := "--:--"
+				progressBar := "[lightgreen]" + strings.Repeat("▓", 30) + "[-] LIVE"
 
-				progress := currentPos / totalDuration
-				if progress > 1 {
-					progress = 1
-				} else if progress < 0 {
-					progress = 0
-				}
+				if totalDuration > 0 {
+					totalTime = formatDuration(int(totalDuration))
 
-				progressBarWidth := 30
-				filledWidth := int(progress * float64(progressBarWidth))
-				progressBar := ""
+					progress := currentPos / totalDuration
+					if progress > 1 {
+						progress = 1
+					} else if progress < 0 {
+						progress = 0
+					}
 
-				for i := range progressBarWidth {
-					if i < filledWidth {
-						progressBar += "[lightgreen]▓"
-					} else {
-						progressBar += "[darkgray]░"
+					progressBarWidth := 30
+					filledWidth := int(progress * float64(progressBarWidth))
+					progressBar = ""
+					loopA, loopB := a.loopPoints()
+					marker := func(i int, at float64) bool {
+						return at >= 0 && i == min(int(at/totalDuration*float64(progressBarWidth)), progressBarWidth-1)
 					}
+
+					for i := range progressBarWidth {
+						if marker(i, loopA) || marker(i, loopB) {
+							progressBar += "[yellow]|"
+						} else if i < filledWidth {
+							progressBar += "[lightgreen]▓"
+						} else {
+							progressBar += "[darkgray]░"
+						}
+					}
+					progressBar += fmt.Sprintf("[-] %.1f%%", progress*100)
 				}
-				progressBar += fmt.Sprintf("[white] %.1f%%", progress*100)
 
 				// 格式化音量显示
 				volumeDisplay := fmt.Sprintf("%.0f%%", volume)
@@ -442,8 +658,27 @@ func (a *Application) updateProgressBar() {
 				}
 
 				progressText := fmt.Sprintf(`
-[darkgray]%s/%s [darkgray][v-] [white]%s[darkgray] [v+] [random]`,
+[darkgray]%s/%s [darkgray][v-] [-]%s[darkgray] [v+] [random]`,
 					currentTime, totalTime, volumeDisplay)
+				progressText += speedDisplay(a.currentSpeed(), currentPos, totalDuration)
+				if loopA, loopB := a.loopPoints(); currentStationPtr == nil && (loopA >= 0 || loopB >= 0) {
+					progressText += " [yellow]" + loopText(loopA, loopB)
+				}
+				progressText += a.sleepDisplay()
+				if a.isRadioMode() {
+					progressText += " [lightgreen](radio)"
+				}
+				if currentSongPtr != nil && currentStationPtr == nil {
+					if bar := a.bufferBar(currentSongPtr.ID); bar != "" {
+						progressBar += "\n" + bar
+					}
+					a.loadingMux.Lock()
+					gainInfo := a.gainInfo
+					a.loadingMux.Unlock()
+					if gainInfo != "" {
+						progressBar += "\n" + gainInfo
+					}
+				}
 
 				select {
 				case <-time.After(10 * time.Millisecond):
@@ -454,9 +689,11 @@ func (a *Application) updateProgressBar() {
 							a.progressBar.SetText(progressText)
 						}
 
-						if currentSongPtr != nil && a.statusBar != nil {
+						if currentStationPtr != nil && a.statusBar != nil {
+							a.statusBar.SetText(stationInfo(currentStationPtr, streamTitle, progressBar))
+						} else if currentSongPtr != nil && a.statusBar != nil {
 							statusInfo := fmt.Sprintf(`
-[white]Current %d:
+[-]Current %d:
 [lightgreen]%s
 
 [darkgray][play] %s
@@ -512,30 +749,25 @@ func (a *Application) createHomepage() {
 
 	a.songTable.SetBorder(false)
 
-	headerStyle := tcell.StyleDefault.Foreground(tcell.ColorGray).Attributes(tcell.AttrBold)
+	a.pageBar = tview.NewTextView().
+		SetDynamicColors(true)
 
-	a.songTable.SetCell(0, 0, tview.NewTableCell("").
-		SetStyle(headerStyle))
-	a.songTable.SetCell(0, 1, tview.NewTableCell("").
-		SetStyle(headerStyle))
-	a.songTable.SetCell(0, 2, tview.NewTableCell("").
-		SetStyle(headerStyle))
-	a.songTable.SetCell(0, 3, tview.NewTableCell("").
-		SetStyle(headerStyle))
-	a.songTable.SetCell(0, 4, tview.NewTableCell("").
-		SetStyle(headerStyle))
+	// 点击表头排序，按住 Shift 点击追加为次要排序
+	a.songTable.SetMouseCapture(func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+		if action != tview.MouseLeftClick {
+			return action, event
+		}
+		row, column := a.songTable.CellAt(event.Position())
+		if row != 0 || column < fixedColumns {
+			return action, event
+		}
+		a.sortColumn(column-fixedColumns+1, event.Modifiers()&tcell.ModShift != 0)
+		return action, nil
+	})
 
 	a.songTable.SetSelectedFunc(func(row, column int) {
-		if row > 0 && row-1 < len(a.totalSongs) {
-			a.loadingMux.Lock()
-			isCurrentlyLoading := a.isLoading
-			a.loadingMux.Unlock()
-
-			if isCurrentlyLoading {
-
-				return
-			}
-			go a.playSongAtIndex(row - 1)
+		if index := a.songIndex(row); row > 0 && index < len(a.totalSongs) {
+			go a.playSongAtIndex(index)
 		}
 	})
 
@@ -543,14 +775,15 @@ func (a *Application) createHomepage() {
 		SetDirection(tview.FlexRow).
 		AddItem(a.statusBar, 0, 1, false)
 
-	rightPanel := tview.NewFlex().
+	a.rightPanel = tview.NewFlex().
 		SetDirection(tview.FlexRow).
-		AddItem(a.songTable, 0, 1, true)
+		AddItem(a.songTable, 0, 1, true).
+		AddItem(a.pageBar, 1, 0, false)
 
 	mainLayout := tview.NewFlex().
 		SetDirection(tview.FlexColumn).
 		AddItem(leftPanel, 0, 1, false).
-		AddItem(rightPanel, 0, 2, true)
+		AddItem(a.rightPanel, 0, 2, true)
 
 	a.rootFlex = tview.NewFlex().
 		SetDirection(tview.FlexRow).
@@ -558,143 +791,21 @@ func (a *Application) createHomepage() {
 		AddItem(a.progressBar, 3, 0, false)
 
 	a.application.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		if event.Key() == tcell.KeyRune {
-			switch event.Rune() {
-			case ' ':
-				if a.mpvInstance == nil || a.mpvInstance.Mpv == nil {
-					return nil
-				}
-
-				go func() {
-					defer func() {
-						if r := recover(); r != nil {
-
-						}
-					}()
-
-					if a.isPlaying {
-						a.mpvInstance.Pause()
-						a.isPlaying = false
-						if a.currentSong != nil {
-							info := fmt.Sprintf(`
-[white]Current %d:
-[yellow]%s [darkgray](PAUSED)
-
-[darkgray][play] %s
-[darkgray][source] %.1f MB
-[darkgray][favourite]
-
-[gray]%s - %s
-[gray]%s
-[darkgray]▓▓▓▓▓▓▓▓░░░░░░░░░░░░░░░░░░░░░░ --%%`,
-								a.currentSongIndex+1,
-								a.currentSong.Title,
-								formatDuration(a.currentSong.Duration),
-								float64(a.currentSong.Size)/1024/1024,
-								a.currentSong.Artist,
-								a.currentSong.Album,
-								a.currentSong.Album)
-
-							a.application.QueueUpdateDraw(func() {
-								if a.statusBar != nil {
-									a.statusBar.SetText(info)
-								}
-							})
-						}
-					} else {
-						a.mpvInstance.Pause()
-						a.isPlaying = true
-						if a.currentSong != nil {
-							info := fmt.Sprintf(`
-[white]Current %d:
-[lightgreen]%s
-
-[darkgray][play] %s
-[darkgray][source] %.1f MB
-[darkgray][favourite]
+		a.searchMux.Lock()
+		overlay := a.overlay
+		a.searchMux.Unlock()
 
-[gray]%s - %s
-[gray]%s
-[lightgreen]▓▓▓▓▓▓▓▓░░░░░░░░░░░░░░░░░░░░░░ --%%`,
-								a.currentSongIndex+1,
-								a.currentSong.Title,
-								formatDuration(a.currentSong.Duration),
-								float64(a.currentSong.Size)/1024/1024,
-								a.currentSong.Artist,
-								a.currentSong.Album,
-								a.currentSong.Album)
-
-							a.application.QueueUpdateDraw(func() {
-								if a.statusBar != nil {
-									a.statusBar.SetText(info)
-								}
-							})
-						}
-					}
-				}()
-				return nil
-			case 'n', 'N':
-				a.playNextSong()
-				return nil
-			case 'p', 'P':
-				a.playPreviousSong()
+		// 悬浮窗口打开时按键交给窗口处理
+		if overlay != "" {
+			if event.Key() == tcell.KeyEsc {
+				a.closeOverlay()
 				return nil
-			case '+', '=': // 增加音量
-				a.SetVolume(true)
-				return nil
-			case '-', '_': // 减少音量
-				a.SetVolume(false)
-				return nil
-			case 'm', 'M': // 静音切换
-				a.muteButton()
-				return nil
-			case '/': // 添加搜索功能
-				a.search()
-				return nil
-			case 'q': // 添加搜索功能
-				go func() {
-					if err := a.loadMusic(); err != nil {
-						a.application.QueueUpdateDraw(func() {
-							a.statusBar.SetText("[red]load music failed: " + err.Error())
-						})
-					}
-				}()
 			}
+			return event
 		}
 
-		switch event.Key() {
-		case tcell.KeyEsc, tcell.KeyCtrlC:
-			// 检查是否处于搜索模式
-			a.searchMux.Lock()
-			isSearching := a.isSearching
-			a.searchMux.Unlock()
-
-			if isSearching {
-				// 如果在搜索模式下，取消搜索而不是退出程序
-				a.application.SetRoot(a.rootFlex, true)
-				a.searchMux.Lock()
-				a.isSearching = false
-				a.searchMux.Unlock()
-				return nil
-			}
-			log.Println("user request exit program")
-
-			if a.mpvInstance != nil && a.mpvInstance.Mpv != nil {
-				a.mpvInstance.Command([]string{"quit"})
-			}
-
-			a.application.Stop()
-
-			go func() {
-				time.Sleep(1 * time.Second)
-				os.Exit(0)
-			}()
-			return nil
-		case tcell.KeyRight:
-			a.playNextSong()
-			return nil
-		case tcell.KeyLeft:
-			a.playPreviousSong()
+		if act, arg, ok := a.actionForKey(event); ok {
+			a.runAction(act, arg)
 			return nil
 		}
 		return event
@@ -702,98 +813,158 @@ func (a *Application) createHomepage() {
 	a.application.SetRoot(a.rootFlex, true)
 
 	welcomeMsg := fmt.Sprintf(`
-[white]Current:
+[-]Current:
 [lightgreen]Welcome to NaviCLI
 
 [darkgray][play] Ready
 [darkgray][source] Navidrome
 [darkgray][favourite]
 
-[gray]Press SPACE to play/pause
-[gray]Press N/P or ←/→ for prev/next
-[gray]Press ESC to exit
-[gray]Select a track to start
-
-[darkgray][red]func[darkgray] [green]navicli[darkgray]([yellow]task[darkgray] [lightblue]string[darkgray]) [lightblue]string[darkgray] {
-[darkgray]    [red]return[darkgray] "^A series of mixes for listening while" [red]+[darkgray] task [red]+[darkgray] \
-[darkgray]         "to focus the brain and i nspire the mind.[darkgray]"
-[darkgray]}
-[darkgray]
-[darkgray]task := "[yellow]programming[darkgray]"
+%s
+[gray]Press [lightgreen]?[gray] for all keys, [lightgreen]:[gray] for commands
 
 [darkgray]// %d songs
 [darkgray]// Written by github.com/yhkl-dev
 [darkgray]// Ready to play
-[darkgray]// Auto-play next enabled`, len(a.totalSongs))
+[darkgray]// Auto-play next enabled`, a.welcomeKeys(), len(a.totalSongs))
 	a.statusBar.SetText(welcomeMsg)
 }
 
-func (a *Application) search() {
-	searchInput, pages := a.searchUI()
-	a.application.SetRoot(pages, true)
-	a.application.SetFocus(searchInput)
+// welcomeKeys 欢迎信息中列出的常用按键，跟随配置中覆盖的按键
+func (a *Application) welcomeKeys() string {
+	var b strings.Builder
+	for _, name := range []string{"pause", "next", "prev", "volup", "voldown", "mute", "search", "find", "albums", "reload", "quit"} {
+		if act, ok := a.findAction(name); ok && len(act.Keys) > 0 {
+			fmt.Fprintf(&b, "[lightgreen]%-8s [gray]%s\n", tview.Escape(act.Keys[0]), act.Help)
+		}
+	}
+	return b.String()
 }
 
-func (a *Application) searchUI() (*tview.InputField, *tview.Pages) {
-	// 创建搜索输入框
-	searchInput := tview.NewInputField().
-		SetLabel("Search: ").
-		SetFieldWidth(30)
+// togglePause 暂停或继续播放
+func (a *Application) togglePause() {
+	if a.mpvInstance == nil || a.mpvInstance.Mpv == nil {
+		return
+	}
+	a.cancelCrossfade("")
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+
+			}
+		}()
+
+		if a.isPlaying {
+			a.mpvInstance.Pause()
+			a.isPlaying = false
+			if a.currentSong != nil {
+				info := fmt.Sprintf(`
+[-]Current %d:
+[yellow]%s [darkgray](PAUSED)
+
+[darkgray][play] %s
+[darkgray][source] %.1f MB
+[darkgray][favourite]
+
+[gray]%s - %s
+[gray]%s
+[darkgray]▓▓▓▓▓▓▓▓░░░░░░░░░░░░░░░░░░░░░░ --%%`,
+					a.currentSongIndex+1,
+					a.currentSong.Title,
+					formatDuration(a.currentSong.Duration),
+					float64(a.currentSong.Size)/1024/1024,
+					a.currentSong.Artist,
+					a.currentSong.Album,
+					a.currentSong.Album)
+
+				a.application.QueueUpdateDraw(func() {
+					if a.statusBar != nil {
+						a.statusBar.SetText(info)
+					}
+				})
+			}
+		} else {
+			a.mpvInstance.Pause()
+			a.isPlaying = true
+			if a.currentSong != nil {
+				info := fmt.Sprintf(`
+[-]Current %d:
+[lightgreen]%s
+
+[darkgray][play] %s
+[darkgray][source] %.1f MB
+[darkgray][favourite]
+
+[gray]%s - %s
+[gray]%s
+[lightgreen]▓▓▓▓▓▓▓▓░░░░░░░░░░░░░░░░░░░░░░ --%%`,
+					a.currentSongIndex+1,
+					a.currentSong.Title,
+					formatDuration(a.currentSong.Duration),
+					float64(a.currentSong.Size)/1024/1024,
+					a.currentSong.Artist,
+					a.currentSong.Album,
+					a.currentSong.Album)
 
-	// 创建一个自定义的模态框来容纳搜索输入框
+				a.application.QueueUpdateDraw(func() {
+					if a.statusBar != nil {
+						a.statusBar.SetText(info)
+					}
+				})
+			}
+		}
+	}()
+}
+
+// showOverlay 在主界面上方显示悬浮窗口，Esc 关闭
+func (a *Application) showOverlay(name string, p tview.Primitive, width, height int) {
 	modalFlex := tview.NewFlex().
 		SetDirection(tview.FlexRow).
 		AddItem(nil, 0, 1, false).
 		AddItem(tview.NewFlex().
 			SetDirection(tview.FlexColumn).
 			AddItem(nil, 0, 1, false).
-			AddItem(searchInput, 40, 0, true).
-			AddItem(nil, 0, 1, false), 3, 0, true).
+			AddItem(p, width, 0, true).
+			AddItem(nil, 0, 1, false), height, 0, true).
 		AddItem(nil, 0, 1, false)
 
-	// 设置背景色实现半透明效果
-	modalFlex.SetBackgroundColor(tcell.ColorBlack)
-	modalFlex.SetBackgroundColor(tcell.GetColor("rgba(0,0,0,0.5)"))
-
-	// 设置搜索输入框的完成函数
-	searchInput.SetDoneFunc(func(key tcell.Key) {
-		a.searchMux.Lock()
-		a.isSearching = false
-		a.searchMux.Unlock()
-
-		if key == tcell.KeyEnter {
-			searchText := searchInput.GetText()
-			if searchText != "" {
-				go func() {
-					if err := a.searchMusic(searchText); err != nil {
-						a.application.QueueUpdateDraw(func() {
-							a.statusBar.SetText("[red]Search failed: " + err.Error())
-						})
-					}
-				}()
-			}
-			// 移除悬浮框，恢复主界面
-			a.application.SetRoot(a.rootFlex, true)
-		} else if key == tcell.KeyEsc {
-			// 取消搜索，移除悬浮框
-			a.application.SetRoot(a.rootFlex, true)
-		}
-	})
-
-	// 使用 Pages 来实现悬浮效果
 	pages := tview.NewPages().
 		AddPage("main", a.rootFlex, true, true).
-		AddPage("search", modalFlex, true, false)
+		AddPage(name, modalFlex, true, true)
+
+	a.searchMux.Lock()
+	a.overlay = name
+	a.searchMux.Unlock()
+
+	a.application.SetRoot(pages, true)
+	a.application.SetFocus(p)
+}
 
-	// 显示搜索页面
-	// 显示搜索页面前设置搜索标志
+func (a *Application) closeOverlay() {
 	a.searchMux.Lock()
-	a.isSearching = true
+	a.overlay = ""
+	onClose := a.overlayClosed
+	a.overlayClosed = nil
 	a.searchMux.Unlock()
 
-	pages.ShowPage("search")
-	return searchInput, pages
+	if onClose != nil {
+		onClose()
+	}
+
+	if a.promptInput != nil {
+		cancel := a.promptCancel
+		a.closePrompt()
+		if cancel != nil {
+			cancel()
+		}
+		return
+	}
+
+	a.application.SetRoot(a.rootFlex, true)
+	a.application.SetFocus(a.songTable)
 }
+
 func (a *Application) SetVolume(addFlag bool) {
 	if a.mpvInstance != nil && a.mpvInstance.Mpv != nil {
 		go func() {
@@ -820,49 +991,48 @@ func (a *Application) SetVolume(addFlag bool) {
 }
 
 func (a *Application) renderSongTable() {
-	// 清除现有行，但保留表头
-	for i := a.songTable.GetRowCount() - 1; i > 0; i-- {
-		a.songTable.RemoveRow(i)
+	a.songTable.Clear()
+
+	columns := a.visibleColumns()
+	sortKeys := a.layout().Sort
+	headerStyle := tcell.StyleDefault.Foreground(tcell.ColorGray).Attributes(tcell.AttrBold)
+
+	a.songTable.SetCell(0, 0, tview.NewTableCell(" ").
+		SetSelectable(false))
+	a.songTable.SetCell(0, 1, tview.NewTableCell("#").
+		SetStyle(headerStyle).
+		SetAlign(tview.AlignRight).
+		SetSelectable(false))
+	for i, col := range columns {
+		a.songTable.SetCell(0, i+fixedColumns, tview.NewTableCell(col.Title+sortMarker(sortKeys, col.Key)).
+			SetStyle(headerStyle).
+			SetAlign(col.Align).
+			SetExpansion(col.Expand).
+			SetSelectable(false))
 	}
 
 	pageData := a.getCurrentPageData()
 
-	// 保存匹配的行索引
-	matchingRows := make([]int, 0)
-
 	for i, song := range pageData {
 		row := i + 1
 
 		rowStyle := tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorDefault)
 
-		trackCell := tview.NewTableCell(fmt.Sprintf("%d:", row)).
+		a.songTable.SetCell(row, 0, a.markCell(song))
+		a.songTable.SetCell(row, 1, tview.NewTableCell(fmt.Sprintf("%d:", a.songIndex(row)+1)).
 			SetStyle(rowStyle.Foreground(tcell.ColorLightGreen)).
-			SetAlign(tview.AlignRight)
-
-		titleCell := tview.NewTableCell(song.Title).
-			SetStyle(rowStyle.Foreground(tcell.ColorWhite)).
-			SetExpansion(1)
-
-		artistCell := tview.NewTableCell(song.Artist).
-			SetStyle(rowStyle.Foreground(tcell.ColorGray)).
-			SetMaxWidth(25)
-
-		albumCell := tview.NewTableCell(song.Album).
-			SetStyle(rowStyle.Foreground(tcell.ColorGray)).
-			SetMaxWidth(25)
-
-		durationCell := tview.NewTableCell(formatDuration(song.Duration)).
-			SetStyle(rowStyle.Foreground(tcell.ColorGray)).
-			SetAlign(tview.AlignRight)
-
-		a.songTable.SetCell(row, 0, trackCell)
-		a.songTable.SetCell(row, 1, titleCell)
-		a.songTable.SetCell(row, 2, artistCell)
-		a.songTable.SetCell(row, 3, albumCell)
-		a.songTable.SetCell(row, 4, durationCell)
-		// 如果是搜索结果，记录匹配的行
-		if len(a.totalSongs) == 1 && reflect.DeepEqual(&a.totalSongs[0], &song) {
-			matchingRows = append(matchingRows, row)
+			SetAlign(tview.AlignRight))
+
+		for j, col := range columns {
+			text := tview.Escape(col.Text(song))
+			if !a.activeFilter.empty() {
+				text = a.activeFilter.highlight(col.Key, col.Text(song))
+			}
+			a.songTable.SetCell(row, j+fixedColumns, tview.NewTableCell(text).
+				SetStyle(rowStyle.Foreground(a.themeColor(col.Color))).
+				SetAlign(col.Align).
+				SetMaxWidth(col.MaxWidth).
+				SetExpansion(col.Expand))
 		}
 	}
 
@@ -871,56 +1041,44 @@ func (a *Application) renderSongTable() {
 		Foreground(tcell.ColorWhite))
 
 	a.songTable.ScrollToBeginning()
+	a.pageBar.SetText(a.pageIndicator())
+}
+
+// beginRefresh 取消尚未完成的列表刷新，返回本次刷新使用的 context
+func (a *Application) beginRefresh() context.Context {
+	a.loadingMux.Lock()
+	defer a.loadingMux.Unlock()
 
-	// 如果有匹配的行，则定位到第一行匹配的行
-	if len(matchingRows) > 0 {
-		a.songTable.Select(matchingRows[0], 0)
+	if a.refreshCancel != nil {
+		a.refreshCancel()
 	}
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.refreshCancel = cancel
+	return ctx
 }
 
 func (a *Application) loadMusic() error {
-	songs, err := a.subsonicClient.GetPlaylists()
+	ctx := a.beginRefresh()
+	songs, err := a.fetchMix(ctx, a.currentMix())
+	// 已被新的刷新取代
+	if ctx.Err() != nil {
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("error get song list: %v", err)
 	}
 
-	if !reflect.DeepEqual(a.totalSongs, songs) {
-		a.totalSongs = songs
-		a.totalPages = (len(a.totalSongs) + a.pageSize - 1) / a.pageSize
-		a.application.QueueUpdateDraw(func() {
-			a.renderSongTable()
-		})
-	}
-	return nil
-}
-func (a *Application) searchMusic(name string) error {
-	matchingSongs := make([]subsonic.Song, 0)
-
-	for _, song := range a.totalSongs {
-		if strings.Contains(strings.ToLower(song.Title), strings.ToLower(name)) ||
-			strings.Contains(strings.ToLower(song.Artist), strings.ToLower(name)) ||
-			strings.Contains(strings.ToLower(song.Album), strings.ToLower(name)) {
-			matchingSongs = append(matchingSongs, song)
-		}
-	}
-
-	if len(matchingSongs) > 0 {
-		a.totalSongs = matchingSongs
-		a.totalPages = (len(a.totalSongs) + a.pageSize - 1) / a.pageSize
-		a.application.QueueUpdateDraw(func() {
-			a.renderSongTable()
-		})
-		return nil
-	}
+	a.stopRadio()
 
-	// 如果没有找到匹配项，显示提示信息
-	a.application.QueueUpdateDraw(func() {
-		a.statusBar.SetText(fmt.Sprintf("[yellow]No results found for: %s", name))
+	var same bool
+	a.syncUpdate(func() {
+		same = reflect.DeepEqual(a.baseSongs, songs)
 	})
-
+	if !same {
+		a.replaceSongs("mix", songs)
+	}
 	return nil
 }
-
 func (a *Application) muteButton() {
 	if a.mpvInstance != nil && a.mpvInstance.Mpv != nil {
 		go func() {
@@ -957,7 +1115,8 @@ func eventListener(ctx context.Context, m *mpv.Mpv) chan *mpv.Event {
 	return c
 }
 
-func ViperInit() {
+// ViperInit 读取配置，配置文件不存在或缺少服务器信息时返回 false，由首次运行向导补全
+func ViperInit() bool {
 	required := []string{
 		"server.url",
 		"server.username",
@@ -970,41 +1129,88 @@ func ViperInit() {
 	viper.AddConfigPath(".")
 
 	viper.SetDefault("keys.search", "/")
+	viper.SetDefault("radio.batch_size", 20)
+	viper.SetDefault("radio.avoid_hours", 24)
+	viper.SetDefault("bookmark.min_duration", 1200)
+	viper.SetDefault("cache.enabled", true)
+	viper.SetDefault("cache.max_size_mb", 2048)
+	viper.SetDefault("ui.page_size", 500)
+	viper.SetDefault("ui.theme", "dark")
+	viper.SetDefault("speed.music", 1.0)
+	viper.SetDefault("speed.podcast", 1.5)
+	viper.SetDefault("speed.audiobook", 1.0)
+	viper.SetDefault("speed.keep_pitch", true)
+	viper.SetDefault("crossfade.seconds", 0)
+	viper.SetDefault("alarm.volume", 50)
+	viper.SetDefault("alarm.fade_seconds", 120)
+	viper.SetDefault("replaygain.mode", "off")
+	viper.SetDefault("replaygain.preamp", 0)
+	viper.SetDefault("replaygain.prevent_clip", true)
+	viper.SetDefault("replaygain.loudnorm_fallback", true)
 
 	if err := viper.ReadInConfig(); err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if errors.As(err, &notFound) {
+			return false
+		}
+		fmt.Fprintln(os.Stderr, "read config failed:", err)
 		os.Exit(1)
 	}
 
 	for _, key := range required {
-		if !viper.IsSet(key) {
-			os.Exit(1)
+		if viper.GetString(key) == "" {
+			return false
 		}
 	}
+	return true
 }
 
 func main() {
-	ViperInit()
+	if !ViperInit() {
+		if err := runOnboarding(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	loadState()
 
-	subsonicClient := subsonic.Init(
-		viper.GetString("server.url"),
-		viper.GetString("server.username"),
-		viper.GetString("server.password"),
-		"goplayer",
-		"1.16.1",
-	)
+	subsonicClient := newClient("server")
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	mpvInstance, err := mpvplayer.CreateMPVInstance()
+	mpvInstance, err := mpvplayer.CreateMPVInstance(playerOptions())
 	if err != nil {
 		log.Fatal(err)
 	}
 	mpvInstance.SetProperty("volume", mpv.FORMAT_DOUBLE, 50.0)
 	app := &Application{
+		ctx:            ctx,
 		application:    tview.NewApplication(),
 		subsonicClient: subsonicClient,
-		mpvInstance:    &mpvplayer.Mpvplayer{mpvInstance, eventListener(ctx, mpvInstance), make([]mpvplayer.QueueItem, 0), false},
+		serverPrefix:   "server",
+		mix:            defaultMix(),
+		mpvInstance: &mpvplayer.Mpvplayer{
+			Mpv:          mpvInstance,
+			EventChannel: eventListener(ctx, mpvInstance),
+			Queue:        make([]mpvplayer.QueueItem, 0),
+		},
+	}
+
+	app.loadEffects()
+	app.loadReplayGain()
+	app.setupCrossfade()
+
+	if streamProxy, err := streamproxy.Start(subsonicClient, openCache("")); err != nil {
+		log.Println("start stream proxy failed:", err)
+	} else {
+		app.streamProxy = streamProxy
+	}
+
+	if store, err := history.Open(dataPath("history.jsonl")); err != nil {
+		log.Println("open history failed:", err)
+	} else {
+		app.history = store
 	}
 
 	sigChan := make(chan os.Signal, 1)
@@ -1029,6 +1235,10 @@ func main() {
 	}()
 
 	app.setupPagination()
+	app.application.EnableMouse(true)
+	if err := app.applyTheme(viper.GetString("ui.theme")); err != nil {
+		log.Println(err)
+	}
 
 	go func() {
 		defer func() {
@@ -1043,7 +1253,35 @@ func main() {
 				if !ok {
 					return
 				}
+				if event != nil && event.Event_Id == mpv.EVENT_FILE_LOADED {
+					go app.onFileLoaded()
+				}
 				if event != nil && event.Event_Id == mpv.EVENT_END_FILE {
+					// 只在播放完毕或出错时自动切到下一首。切歌、切换服务器等主动停止（STOP）
+					// 由发起方决定接下来播放什么
+					eof, advance := false, false
+					if ef, ok := event.Data.(mpv.EventEndFile); ok {
+						switch ef.Reason {
+						case mpv.END_FILE_REASON_EOF:
+							app.recordPlay(playCompleted)
+							eof, advance = true, true
+						case mpv.END_FILE_REASON_ERROR:
+							// 播放出错后自动切到下一首，不算用户跳过
+							app.recordPlay(playStopped)
+							advance = true
+						}
+					}
+					if !advance {
+						continue
+					}
+					// 网络电台断流时不自动切到下一首
+					if app.isPlayingStation() {
+						continue
+					}
+					// 睡眠定时器在歌曲或专辑播完时停止
+					if eof && app.sleepStopsPlayback() {
+						continue
+					}
 					app.application.QueueUpdateDraw(func() {
 						app.playNextSong()
 					})
@@ -1055,7 +1293,14 @@ func main() {
 	}()
 
 	go app.updateProgressBar()
+	go app.watchCrossfade()
 	go func() {
+		if _, err := subsonicClient.Negotiate(ctx); err != nil {
+			log.Println("negotiate server capabilities failed:", err)
+		}
+		if err := app.applyDefaultMusicFolder(); err != nil {
+			log.Println(err)
+		}
 		if err := app.loadMusic(); err != nil {
 			app.application.QueueUpdateDraw(func() {
 				app.statusBar.SetText("[red]load music failed: " + err.Error())
@@ -1068,8 +1313,14 @@ func main() {
 	err = app.application.Run()
 
 	log.Println("program exiting, clear resource...")
+	app.recordPlay(playStopped)
+	app.recordWG.Wait()
 	cancel()
 
+	if app.crossfader != nil {
+		app.crossfader.Stop(app.mpvInstance)
+		app.crossfader.Close()
+	}
 	if app.mpvInstance != nil && app.mpvInstance.Mpv != nil {
 		func() {
 			defer func() {
@@ -1082,6 +1333,10 @@ func main() {
 		}()
 	}
 
+	if app.streamProxy != nil {
+		app.streamProxy.Close()
+	}
+
 	if err != nil {
 		fmt.Println(err.Error())
 		os.Exit(1)
@@ -0,0 +1,320 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/yhkl-dev/NaviCLI/subsonic"
+)
+
+// toggleMark 标记或取消标记选中的歌曲，并移动到下一行
+func (a *Application) toggleMark() {
+	row, _ := a.songTable.GetSelection()
+	index := a.songIndex(row)
+	if row <= 0 || index >= len(a.totalSongs) {
+		return
+	}
+
+	if a.marked == nil {
+		a.marked = make(map[string]bool)
+	}
+	id := a.totalSongs[index].ID
+	if a.marked[id] {
+		delete(a.marked, id)
+	} else {
+		a.marked[id] = true
+	}
+	a.markAnchor = index
+
+	a.renderSongTable()
+	a.showSong(min(index+1, len(a.totalSongs)-1))
+}
+
+// markRange 标记上一次标记的歌曲到选中歌曲之间的所有歌曲
+func (a *Application) markRange() {
+	row, _ := a.songTable.GetSelection()
+	index := a.songIndex(row)
+	if row <= 0 || index >= len(a.totalSongs) {
+		return
+	}
+	if a.markAnchor < 0 || a.markAnchor >= len(a.totalSongs) {
+		a.toggleMark()
+		return
+	}
+
+	if a.marked == nil {
+		a.marked = make(map[string]bool)
+	}
+	from, to := min(a.markAnchor, index), max(a.markAnchor, index)
+	for _, song := range a.totalSongs[from : to+1] {
+		a.marked[song.ID] = true
+	}
+	a.markAnchor = index
+
+	a.renderSongTable()
+	a.showSong(index)
+}
+
+func (a *Application) clearMarks() {
+	a.marked = nil
+	a.markAnchor = -1
+	a.renderSongTable()
+}
+
+// targetSongs 批量操作的歌曲：有标记时为所有标记的歌曲（按列表顺序），否则为选中的歌曲
+func (a *Application) targetSongs() []subsonic.Song {
+	var songs []subsonic.Song
+	for _, song := range a.totalSongs {
+		if a.marked[song.ID] {
+			songs = append(songs, song)
+		}
+	}
+	if len(songs) == 0 {
+		if song, ok := a.selectedSong(); ok {
+			songs = append(songs, song)
+		}
+	}
+	return songs
+}
+
+func songIDs(songs []subsonic.Song) []string {
+	ids := make([]string, len(songs))
+	for i, song := range songs {
+		ids[i] = song.ID
+	}
+	return ids
+}
+
+// setMessage 在表格下方显示提示信息
+func (a *Application) setMessage(text string) {
+	a.application.QueueUpdateDraw(func() {
+		a.message = text
+		a.pageBar.SetText(a.pageIndicator())
+	})
+}
+
+// showActions 打开批量操作菜单
+func (a *Application) showActions() {
+	songs := a.targetSongs()
+	if len(songs) == 0 {
+		return
+	}
+
+	list := tview.NewList().ShowSecondaryText(false)
+	list.SetBorder(true).SetTitle(fmt.Sprintf(" %d songs ", len(songs)))
+
+	run := func(action func()) func() {
+		return func() {
+			a.closeOverlay()
+			action()
+		}
+	}
+	list.AddItem("Enqueue", "", 'e', run(func() {
+		a.enqueue(songs)
+		a.setMessage(fmt.Sprintf("[lightgreen]%d songs enqueued", len(songs)))
+	}))
+	list.AddItem("Play next", "", 'n', run(func() {
+		a.playNext(songs)
+		a.setMessage(fmt.Sprintf("[lightgreen]%d songs will play next", len(songs)))
+	}))
+	list.AddItem("Add to playlist...", "", 'p', func() {
+		a.showPlaylistPicker(songs)
+	})
+	list.AddItem("Star", "", 's', run(func() {
+		go a.starSongs(songs, true)
+	}))
+	list.AddItem("Unstar", "", 'S', run(func() {
+		go a.starSongs(songs, false)
+	}))
+	list.AddItem("Download for offline", "", 'd', run(func() {
+		go a.downloadSongs(songs)
+	}))
+	list.AddItem("Copy share link", "", 'l', run(func() {
+		go a.shareSongs(songs)
+	}))
+	list.AddItem("Clear marks", "", 'u', run(a.clearMarks))
+
+	a.showOverlay("actions", list, 40, 10)
+}
+
+func (a *Application) starSongs(songs []subsonic.Song, star bool) {
+	var err error
+	if star {
+		err = a.subsonicClient.StarContext(a.ctx, songIDs(songs))
+	} else {
+		err = a.subsonicClient.UnstarContext(a.ctx, songIDs(songs))
+	}
+	if err != nil {
+		a.setMessage("[red]star failed: " + err.Error())
+		return
+	}
+
+	var starred time.Time
+	if star {
+		starred = time.Now()
+	}
+	ids := make(map[string]bool, len(songs))
+	for _, song := range songs {
+		ids[song.ID] = true
+	}
+	a.application.QueueUpdateDraw(func() {
+		for i := range a.baseSongs {
+			if ids[a.baseSongs[i].ID] {
+				a.baseSongs[i].Starred = starred
+			}
+		}
+		for i := range a.totalSongs {
+			if ids[a.totalSongs[i].ID] {
+				a.totalSongs[i].Starred = starred
+			}
+		}
+		a.renderSongTable()
+	})
+	if star {
+		a.setMessage(fmt.Sprintf("[lightgreen]%d songs starred", len(songs)))
+	} else {
+		a.setMessage(fmt.Sprintf("[lightgreen]%d songs unstarred", len(songs)))
+	}
+}
+
+// downloadSongs 依次下载到离线缓存
+func (a *Application) downloadSongs(songs []subsonic.Song) {
+	if a.streamProxy == nil {
+		a.setMessage("[red]offline cache is not available")
+		return
+	}
+	failed := 0
+	for i, song := range songs {
+		a.setMessage(fmt.Sprintf("[yellow]downloading %d/%d: %s", i+1, len(songs), tview.Escape(song.Title)))
+		if err := a.streamProxy.Save(a.ctx, song.ID); err != nil {
+			if a.ctx.Err() != nil {
+				return
+			}
+			failed++
+		}
+	}
+	if failed > 0 {
+		a.setMessage(fmt.Sprintf("[red]%d of %d downloads failed", failed, len(songs)))
+		return
+	}
+	a.setMessage(fmt.Sprintf("[lightgreen]%d songs available offline", len(songs)))
+}
+
+// shareSongs 创建分享链接并复制到剪贴板
+func (a *Application) shareSongs(songs []subsonic.Song) {
+	description := songs[0].Title
+	if len(songs) > 1 {
+		description = fmt.Sprintf("%s and %d more", songs[0].Title, len(songs)-1)
+	}
+
+	ctx, cancel := context.WithTimeout(a.ctx, 15*time.Second)
+	defer cancel()
+	share, err := a.subsonicClient.CreateShareContext(ctx, songIDs(songs), description)
+	if err != nil {
+		a.setMessage("[red]create share failed: " + err.Error())
+		return
+	}
+
+	text := "Share link"
+	if err := copyToClipboard(share.URL); err == nil {
+		text += " (copied)"
+	}
+	a.application.QueueUpdateDraw(func() {
+		modal := tview.NewModal().
+			SetText(text + ":\n\n" + share.URL).
+			AddButtons([]string{"OK"}).
+			SetDoneFunc(func(int, string) {
+				a.closeOverlay()
+			})
+		a.showOverlay("share", modal, 70, 9)
+	})
+}
+
+// showPlaylistPicker 选择要添加到的播放列表，或新建播放列表
+func (a *Application) showPlaylistPicker(songs []subsonic.Song) {
+	go func() {
+		playlists, err := a.subsonicClient.ListPlaylistsContext(a.ctx)
+		if err != nil {
+			a.application.QueueUpdateDraw(func() {
+				a.closeOverlay()
+				a.setMessage("[red]load playlists failed: " + err.Error())
+			})
+			return
+		}
+		a.application.QueueUpdateDraw(func() {
+			a.playlistPickerUI(songs, playlists)
+		})
+	}()
+}
+
+func (a *Application) playlistPickerUI(songs []subsonic.Song, playlists []subsonic.Playlist) {
+	list := tview.NewList()
+	list.SetBorder(true).SetTitle(fmt.Sprintf(" Add %d songs to playlist ", len(songs)))
+	list.AddItem("[lightgreen]+ New playlist", "", 0, func() {
+		a.newPlaylistForm(songs)
+	})
+	for _, playlist := range playlists {
+		list.AddItem(tview.Escape(playlist.Name),
+			fmt.Sprintf("[darkgray]%d songs  %s", playlist.SongCount, formatDuration(playlist.Duration)), 0, nil)
+	}
+
+	list.SetSelectedFunc(func(index int, _, _ string, _ rune) {
+		if index == 0 {
+			return
+		}
+		playlist := playlists[index-1]
+		a.closeOverlay()
+		go func() {
+			if err := a.subsonicClient.AddToPlaylistContext(a.ctx, playlist.ID, songIDs(songs)); err != nil {
+				a.setMessage("[red]add to playlist failed: " + err.Error())
+				return
+			}
+			a.setMessage(fmt.Sprintf("[lightgreen]%d songs added to %s", len(songs), tview.Escape(playlist.Name)))
+		}()
+	})
+
+	a.showOverlay("playlists", list, 60, 20)
+}
+
+func (a *Application) newPlaylistForm(songs []subsonic.Song) {
+	form := tview.NewForm().
+		AddInputField("Name", "", 40, nil, nil)
+	form.SetBorder(true).SetTitle(" New playlist ")
+
+	form.AddButton("Create", func() {
+		name := strings.TrimSpace(form.GetFormItemByLabel("Name").(*tview.InputField).GetText())
+		if name == "" {
+			return
+		}
+		a.closeOverlay()
+		go func() {
+			if err := a.subsonicClient.CreatePlaylistContext(a.ctx, name, songIDs(songs)); err != nil {
+				a.setMessage("[red]create playlist failed: " + err.Error())
+				return
+			}
+			a.setMessage(fmt.Sprintf("[lightgreen]playlist %s created with %d songs", tview.Escape(name), len(songs)))
+		}()
+	})
+	form.AddButton("Cancel", func() {
+		a.closeOverlay()
+	})
+	form.SetCancelFunc(func() {
+		a.closeOverlay()
+	})
+
+	a.showOverlay("playlist-new", form, 60, 7)
+}
+
+// markCell 标记列的单元格
+func (a *Application) markCell(song subsonic.Song) *tview.TableCell {
+	text := " "
+	if a.marked[song.ID] {
+		text = "●"
+	}
+	return tview.NewTableCell(text).
+		SetStyle(tcell.StyleDefault.Foreground(tcell.ColorYellow))
+}
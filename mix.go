@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/yhkl-dev/NaviCLI/subsonic"
+)
+
+// mixOptions 随机混音的筛选条件，q 刷新时按当前条件重新生成
+type mixOptions struct {
+	Genres   []string
+	FromYear int
+	ToYear   int
+	Size     int
+}
+
+func defaultMix() mixOptions {
+	return mixOptions{Size: 500}
+}
+
+func (m mixOptions) String() string {
+	parts := make([]string, 0, 3)
+	if len(m.Genres) > 0 {
+		parts = append(parts, strings.Join(m.Genres, ", "))
+	}
+	if m.FromYear > 0 || m.ToYear > 0 {
+		parts = append(parts, fmt.Sprintf("%s-%s", yearText(m.FromYear), yearText(m.ToYear)))
+	}
+	if len(parts) == 0 {
+		parts = append(parts, "All")
+	}
+	return fmt.Sprintf("%s (%d songs)", strings.Join(parts, " / "), m.Size)
+}
+
+func yearText(year int) string {
+	if year <= 0 {
+		return ""
+	}
+	return strconv.Itoa(year)
+}
+
+func (a *Application) currentMix() mixOptions {
+	a.loadingMux.Lock()
+	defer a.loadingMux.Unlock()
+	return a.mix
+}
+
+// fetchMix 按筛选条件获取随机歌曲，选择多个流派时按流派平分数量
+func (a *Application) fetchMix(ctx context.Context, m mixOptions) ([]subsonic.Song, error) {
+	opts := subsonic.RandomSongsOptions{
+		Size:     m.Size,
+		FromYear: m.FromYear,
+		ToYear:   m.ToYear,
+	}
+	if len(m.Genres) == 0 {
+		return a.subsonicClient.GetRandomSongsContext(ctx, opts)
+	}
+
+	opts.Size = max(m.Size/len(m.Genres), 1)
+	songs := make([]subsonic.Song, 0, m.Size)
+	for _, genre := range m.Genres {
+		opts.Genre = genre
+		genreSongs, err := a.subsonicClient.GetRandomSongsContext(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		songs = append(songs, genreSongs...)
+	}
+
+	rand.Shuffle(len(songs), func(i, j int) {
+		songs[i], songs[j] = songs[j], songs[i]
+	})
+	return songs, nil
+}
+
+// showMixBuilder 打开混音生成对话框，左侧选择流派，右侧设置年份、音乐库和数量
+func (a *Application) showMixBuilder() {
+	go func() {
+		genres, err := a.subsonicClient.GetGenresContext(a.ctx)
+		if err != nil {
+			a.application.QueueUpdateDraw(func() {
+				a.statusBar.SetText("[red]load genres failed: " + err.Error())
+			})
+			return
+		}
+		folders, err := a.subsonicClient.GetMusicFoldersContext(a.ctx)
+		if err != nil {
+			a.application.QueueUpdateDraw(func() {
+				a.statusBar.SetText("[red]load music folders failed: " + err.Error())
+			})
+			return
+		}
+		sort.Slice(genres, func(i, j int) bool {
+			return strings.ToLower(genres[i].Value) < strings.ToLower(genres[j].Value)
+		})
+
+		a.application.QueueUpdateDraw(func() {
+			a.mixBuilderUI(genres, folders)
+		})
+	}()
+}
+
+func (a *Application) mixBuilderUI(genres []subsonic.Genre, folders []subsonic.MusicFolder) {
+	mix := a.currentMix()
+
+	selected := make(map[string]bool, len(mix.Genres))
+	for _, genre := range mix.Genres {
+		selected[genre] = true
+	}
+
+	genreText := func(g subsonic.Genre) string {
+		mark := "[ ]"
+		if selected[g.Value] {
+			mark = "[x]"
+		}
+		return fmt.Sprintf("%s %s (%d)", tview.Escape(mark), tview.Escape(g.Value), g.SongCount)
+	}
+
+	genreList := tview.NewList().ShowSecondaryText(false)
+	genreList.SetBorder(true).SetTitle(" Genres (Enter to toggle) ")
+	for _, g := range genres {
+		genreList.AddItem(genreText(g), "", 0, nil)
+	}
+	genreList.SetSelectedFunc(func(index int, _, _ string, _ rune) {
+		g := genres[index]
+		selected[g.Value] = !selected[g.Value]
+		genreList.SetItemText(index, genreText(g), "")
+	})
+
+	form := tview.NewForm()
+	form.SetBorder(true).SetTitle(" Mix ")
+	form.AddInputField("From year", yearText(mix.FromYear), 6, tview.InputFieldInteger, nil)
+	form.AddInputField("To year", yearText(mix.ToYear), 6, tview.InputFieldInteger, nil)
+	folderOptions, folderIndex := a.musicFolderOptions(folders)
+	form.AddDropDown("Library", folderOptions, folderIndex, nil)
+	form.AddInputField("Size", strconv.Itoa(mix.Size), 6, tview.InputFieldInteger, nil)
+
+	inputInt := func(label string) int {
+		value, _ := strconv.Atoi(form.GetFormItemByLabel(label).(*tview.InputField).GetText())
+		return value
+	}
+
+	form.AddButton("Build", func() {
+		m := mixOptions{
+			FromYear: inputInt("From year"),
+			ToYear:   inputInt("To year"),
+			Size:     min(max(inputInt("Size"), 1), 500),
+		}
+		for _, g := range genres {
+			if selected[g.Value] {
+				m.Genres = append(m.Genres, g.Value)
+			}
+		}
+
+		a.loadingMux.Lock()
+		a.mix = m
+		a.loadingMux.Unlock()
+
+		folderID := ""
+		if index, _ := form.GetFormItemByLabel("Library").(*tview.DropDown).GetCurrentOption(); index > 0 {
+			folderID = strconv.Itoa(folders[index-1].ID)
+		}
+		a.subsonicClient.SetMusicFolder(folderID)
+
+		a.closeOverlay()
+		go func() {
+			if err := a.loadMusic(); err != nil {
+				a.application.QueueUpdateDraw(func() {
+					a.statusBar.SetText("[red]load music failed: " + err.Error())
+				})
+				return
+			}
+			a.application.QueueUpdateDraw(func() {
+				a.statusBar.SetText("[-]Mix:\n[lightgreen]" + tview.Escape(m.String()))
+			})
+		}()
+	})
+	form.AddButton("Genres", func() {
+		a.application.SetFocus(genreList)
+	})
+	form.AddButton("Cancel", func() {
+		a.closeOverlay()
+	})
+
+	genreList.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyTab {
+			a.application.SetFocus(form)
+			return nil
+		}
+		return event
+	})
+
+	layout := tview.NewFlex().
+		SetDirection(tview.FlexColumn).
+		AddItem(genreList, 0, 1, true).
+		AddItem(form, 36, 0, false)
+
+	a.showOverlay("mix", layout, 80, 24)
+}
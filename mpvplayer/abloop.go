@@ -0,0 +1,18 @@
+package mpvplayer
+
+import "strconv"
+
+// SetABLoop 设置 mpv 的 A-B 循环点（秒，相对于当前文件），负数表示清除该点
+func (m *Mpvplayer) SetABLoop(a, b float64) error {
+	if err := m.SetPropertyString("ab-loop-a", loopPoint(a)); err != nil {
+		return err
+	}
+	return m.SetPropertyString("ab-loop-b", loopPoint(b))
+}
+
+func loopPoint(seconds float64) string {
+	if seconds < 0 {
+		return "no"
+	}
+	return strconv.FormatFloat(seconds, 'f', 3, 64)
+}
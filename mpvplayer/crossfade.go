@@ -0,0 +1,176 @@
+package mpvplayer
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/wildeyedskies/go-mpv/mpv"
+)
+
+// MaxCrossfade 淡入淡出的最长时间
+const MaxCrossfade = 12 * time.Second
+
+// crossfadeFilter 主实例上控制淡入音量的滤镜，音量通过 af-command 调整，不影响用户设置的 volume
+const crossfadeFilter = "lavfi=[volume=volume=1]"
+
+// Crossfader 用第二个 mpv 实例接着播放上一首的结尾并逐渐降低音量，
+// 同时主实例播放下一首并逐渐提高音量。两边的音量都按各自的播放位置计算
+type Crossfader struct {
+	fader *mpv.Mpv
+
+	mu     sync.Mutex
+	ctx    context.Context
+	cancel context.CancelFunc
+	next   string // 正在淡入的歌曲 ID
+}
+
+// NewCrossfader 创建用于淡出的第二个 mpv 实例
+func NewCrossfader(opts Options) (*Crossfader, error) {
+	fader, err := CreateMPVInstance(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Crossfader{fader: fader}, nil
+}
+
+// EnableCrossfade 在主实例上安装淡入滤镜，enabled 为 false 时移除
+func (m *Mpvplayer) EnableCrossfade(enabled bool) error {
+	if enabled {
+		return m.SetFilter("crossfade", crossfadeFilter)
+	}
+	return m.SetFilter("crossfade", "")
+}
+
+// setCrossfadeLevel 设置淡入滤镜的音量，0 到 1
+func (m *Mpvplayer) setCrossfadeLevel(level float64) error {
+	return m.Command([]string{"af-command", "crossfade", "volume", strconv.FormatFloat(level, 'f', 3, 64)})
+}
+
+// Start 在副实例上从 start 秒继续播放 url（上一首），复制主实例的输出设备、滤镜、速度和音量，
+// 加载完成后把主实例的淡入音量设为 0。随后主实例加载 next，再调用 Run 开始淡入淡出
+func (c *Crossfader) Start(main *Mpvplayer, url string, start float64, next string) error {
+	c.Stop(main)
+
+	for _, name := range []string{"audio-device", "af", "speed", "audio-pitch-correction", "replaygain", "replaygain-preamp", "replaygain-clip", "volume", "mute"} {
+		if value := main.GetPropertyString(name); value != "" {
+			c.fader.SetPropertyString(name, value)
+		}
+	}
+	c.fader.SetPropertyString("start", strconv.FormatFloat(start, 'f', 3, 64))
+	if err := c.fader.Command([]string{"loadfile", url}); err != nil {
+		return err
+	}
+
+	// 等副实例开始播放后再切换主实例，避免中间出现空白
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		event := c.fader.WaitEvent(0.1)
+		if event != nil && event.Event_Id == mpv.EVENT_FILE_LOADED {
+			break
+		}
+		if time.Now().After(deadline) {
+			c.fader.Command([]string{"stop"})
+			return errors.New("crossfade: load previous track timed out")
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.mu.Lock()
+	c.ctx, c.cancel, c.next = ctx, cancel, next
+	c.mu.Unlock()
+	return main.setCrossfadeLevel(0)
+}
+
+// Run 按播放位置同时淡出副实例、淡入主实例，fade 后停止副实例并恢复主实例音量。
+// 被 Stop 取消时由 Stop 负责恢复
+func (c *Crossfader) Run(main *Mpvplayer, fade time.Duration) {
+	c.mu.Lock()
+	ctx := c.ctx
+	c.mu.Unlock()
+	if ctx == nil {
+		return
+	}
+
+	volume := 100.0
+	if v, err := c.fader.GetProperty("volume", mpv.FORMAT_DOUBLE); err == nil {
+		volume = v.(float64)
+	}
+	position := func(m *mpv.Mpv) (float64, bool) {
+		pos, err := m.GetProperty("time-pos", mpv.FORMAT_DOUBLE)
+		if err != nil {
+			return 0, false
+		}
+		return pos.(float64), true
+	}
+
+	// 各自从第一次读到的位置开始计算进度；副实例播完时淡出结束
+	var fadeOutFrom, fadeInFrom float64 = -1, -1
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		outDone := true
+		if pos, ok := position(c.fader); ok {
+			if fadeOutFrom < 0 {
+				fadeOutFrom = pos
+			}
+			progress := min((pos-fadeOutFrom)/fade.Seconds(), 1)
+			c.fader.SetProperty("volume", mpv.FORMAT_DOUBLE, volume*(1-progress))
+			outDone = progress >= 1
+		}
+
+		inDone := false
+		if pos, ok := position(main.Mpv); ok {
+			if fadeInFrom < 0 {
+				fadeInFrom = pos
+			}
+			progress := min(max((pos-fadeInFrom)/fade.Seconds(), 0), 1)
+			main.setCrossfadeLevel(progress)
+			inDone = progress >= 1
+		}
+
+		if outDone && inDone {
+			c.mu.Lock()
+			current := c.ctx == ctx
+			c.mu.Unlock()
+			if current {
+				c.Stop(main)
+			}
+			return
+		}
+	}
+}
+
+// Next 正在淡入的歌曲 ID，没有进行中的淡入淡出时返回空字符串
+func (c *Crossfader) Next() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.next
+}
+
+// Stop 取消进行中的淡入淡出：停止副实例并恢复主实例音量
+func (c *Crossfader) Stop(main *Mpvplayer) {
+	c.mu.Lock()
+	cancel := c.cancel
+	c.ctx, c.cancel, c.next = nil, nil, ""
+	c.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	c.fader.Command([]string{"stop"})
+	main.setCrossfadeLevel(1)
+}
+
+// Close 销毁副实例
+func (c *Crossfader) Close() {
+	c.fader.TerminateDestroy()
+}
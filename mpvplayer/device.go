@@ -0,0 +1,38 @@
+package mpvplayer
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// AudioDevice mpv audio-device-list 中的输出设备
+type AudioDevice struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// AudioDevices 列出可用的输出设备，第一项通常是 auto（系统默认）
+func (m *Mpvplayer) AudioDevices() ([]AudioDevice, error) {
+	var devices []AudioDevice
+	// 以字符串读取节点类型的属性时 mpv 返回 JSON
+	if err := json.Unmarshal([]byte(m.GetPropertyString("audio-device-list")), &devices); err != nil {
+		return nil, fmt.Errorf("read audio-device-list: %w", err)
+	}
+	return devices, nil
+}
+
+// AudioDevice 当前输出设备的名称
+func (m *Mpvplayer) AudioDevice() string {
+	if name := m.GetPropertyString("audio-device"); name != "" {
+		return name
+	}
+	return "auto"
+}
+
+// SetAudioDevice 切换输出设备，mpv 重新打开音频输出，当前歌曲继续播放
+func (m *Mpvplayer) SetAudioDevice(name string) error {
+	if name == "" {
+		name = "auto"
+	}
+	return m.SetPropertyString("audio-device", name)
+}
@@ -0,0 +1,99 @@
+package mpvplayer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// EqualizerBands 10 段均衡器的中心频率（Hz），每段一个倍频程
+var EqualizerBands = [10]float64{31, 62, 125, 250, 500, 1000, 2000, 4000, 8000, 16000}
+
+// 均衡器每段的增益范围（dB）
+const (
+	MinGain = -12
+	MaxGain = 12
+)
+
+// Presets 均衡器预设，数值为每段增益（dB）
+var Presets = map[string][10]int{
+	"flat":       {},
+	"bass boost": {6, 5, 4, 2, 0, 0, 0, 0, 0, 0},
+	"vocal":      {-2, -2, -1, 0, 2, 4, 4, 3, 1, 0},
+	"loudness":   {5, 4, 2, 0, -1, -1, 0, 2, 4, 5},
+}
+
+// PresetNames 按固定顺序返回预设名称
+func PresetNames() []string {
+	return []string{"flat", "bass boost", "vocal", "loudness"}
+}
+
+// Effects 音效设置：均衡器、夜间模式的动态范围压缩和单声道混音
+type Effects struct {
+	Gains      [10]int
+	Compressor bool
+	Mono       bool
+}
+
+// Preset 返回与当前均衡器一致的预设名称，没有时返回空字符串
+func (e Effects) Preset() string {
+	for _, name := range PresetNames() {
+		if Presets[name] == e.Gains {
+			return name
+		}
+	}
+	return ""
+}
+
+// Graph 返回 lavfi 滤镜图，没有启用任何音效时返回空字符串
+func (e Effects) Graph() string {
+	var filters []string
+	for i, gain := range e.Gains {
+		if gain != 0 {
+			filters = append(filters, fmt.Sprintf("equalizer=f=%g:t=o:w=1:g=%d", EqualizerBands[i], gain))
+		}
+	}
+	if e.Compressor {
+		filters = append(filters, "acompressor=threshold=0.1:ratio=4:attack=5:release=250:makeup=2")
+	}
+	if e.Mono {
+		filters = append(filters, "pan=stereo|c0=0.5*c0+0.5*c1|c1=0.5*c0+0.5*c1")
+	}
+	return strings.Join(filters, ",")
+}
+
+// SetEffects 应用音效设置，播放中立即生效
+func (m *Mpvplayer) SetEffects(e Effects) error {
+	graph := e.Graph()
+	if graph == "" {
+		return m.SetFilter("effects", "")
+	}
+	return m.SetFilter("effects", "lavfi=["+graph+"]")
+}
+
+// SetFilter 设置带标签的音频滤镜，filter 为空时移除。
+// 不同功能使用各自的标签，按首次设置的顺序组成 mpv 的 af 属性
+func (m *Mpvplayer) SetFilter(label, filter string) error {
+	m.filterMu.Lock()
+	defer m.filterMu.Unlock()
+
+	if m.filters == nil {
+		m.filters = make(map[string]string)
+	}
+	if filter == "" {
+		delete(m.filters, label)
+	} else {
+		if !slices.Contains(m.filterOrder, label) {
+			m.filterOrder = append(m.filterOrder, label)
+		}
+		m.filters[label] = filter
+	}
+
+	var chain []string
+	for _, l := range m.filterOrder {
+		if f, ok := m.filters[l]; ok {
+			chain = append(chain, "@"+l+":"+f)
+		}
+	}
+	return m.SetPropertyString("af", strings.Join(chain, ","))
+}
@@ -1,6 +1,9 @@
 package mpvplayer
 
 import (
+	"strconv"
+	"sync"
+
 	"github.com/wildeyedskies/go-mpv/mpv"
 )
 
@@ -24,6 +27,12 @@ type Mpvplayer struct {
 	EventChannel      chan *mpv.Event
 	Queue             []QueueItem
 	ReplaceInProgress bool
+
+	// 带标签的音频滤镜，见 SetFilter。界面、加载歌曲和 FILE_LOADED 事件的 goroutine 都会调用，
+	// filterMu 保护两者以及 af 属性的写入
+	filterMu    sync.Mutex
+	filters     map[string]string
+	filterOrder []string
 }
 
 func (m *Mpvplayer) GetProgress() (float64, error) {
@@ -36,7 +45,22 @@ func (m *Mpvplayer) GetDuration() (float64, error) {
 	return duration.(float64), err
 }
 
+// GetStreamTitle 返回网络电台 ICY 元数据中的当前曲目，没有时返回空字符串
+func (m *Mpvplayer) GetStreamTitle() string {
+	return m.GetPropertyString("metadata/by-key/icy-title")
+}
+
 func (m *Mpvplayer) Play(playURL string) {
+	m.PlayFrom(playURL, 0)
+}
+
+// PlayFrom 从 start 秒处开始播放，通过 mpv 的 start 选项在加载时定位
+func (m *Mpvplayer) PlayFrom(playURL string, start int) {
+	if start > 0 {
+		m.SetPropertyString("start", strconv.Itoa(start))
+	} else {
+		m.SetPropertyString("start", "none")
+	}
 	m.Command([]string{"loadfile", playURL})
 }
 
@@ -83,11 +107,27 @@ func (m *Mpvplayer) Pause() (int, error) {
 	}
 }
 
-func CreateMPVInstance() (*mpv.Mpv, error) {
+// Options 创建 mpv 实例时的音频输出选项
+type Options struct {
+	AudioDevice string  // 输出设备名称，空为 auto
+	Exclusive   bool    // 独占输出设备（WASAPI、CoreAudio 等），绕过系统混音
+	Buffer      float64 // 音频输出缓冲（秒），0 使用 mpv 默认值
+}
+
+func CreateMPVInstance(opts Options) (*mpv.Mpv, error) {
 	mpvInstance := mpv.Create()
 
 	mpvInstance.SetOptionString("audio-display", "no")
 	mpvInstance.SetOptionString("video", "no")
+	if opts.AudioDevice != "" {
+		mpvInstance.SetOptionString("audio-device", opts.AudioDevice)
+	}
+	if opts.Exclusive {
+		mpvInstance.SetOptionString("audio-exclusive", "yes")
+	}
+	if opts.Buffer > 0 {
+		mpvInstance.SetOptionString("audio-buffer", strconv.FormatFloat(opts.Buffer, 'f', -1, 64))
+	}
 	mpvInstance.ObserveProperty(0, "cache-buffering-state", mpv.FORMAT_INT64)
 	mpvInstance.ObserveProperty(0, "demuxer-cache-duration", mpv.FORMAT_INT64)
 
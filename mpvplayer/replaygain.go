@@ -0,0 +1,92 @@
+package mpvplayer
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/wildeyedskies/go-mpv/mpv"
+)
+
+// ReplayGain 模式
+const (
+	ReplayGainOff   = "off"
+	ReplayGainTrack = "track"
+	ReplayGainAlbum = "album"
+)
+
+// loudnormFilter 没有 ReplayGain 标签时使用的响度标准化（EBU R128，-16 LUFS）
+const loudnormFilter = "lavfi=[loudnorm=I=-16:TP=-1.5:LRA=11]"
+
+// ReplayGain 音量标准化设置
+type ReplayGain struct {
+	Mode        string  // off、track 或 album
+	Preamp      float64 // 在标签增益之上额外调整的 dB
+	PreventClip bool    // 按峰值降低增益避免削波
+	Loudnorm    bool    // 缺少标签时使用 loudnorm 代替
+}
+
+// SetReplayGain 设置 mpv 的 replaygain 选项，从下一首开始生效
+func (m *Mpvplayer) SetReplayGain(rg ReplayGain) error {
+	mode := rg.Mode
+	if mode == ReplayGainOff || mode == "" {
+		mode = "no"
+	}
+	if err := m.SetPropertyString("replaygain", mode); err != nil {
+		return err
+	}
+	if err := m.SetPropertyString("replaygain-preamp", strconv.FormatFloat(rg.Preamp, 'f', 1, 64)); err != nil {
+		return err
+	}
+	clip := "no"
+	if rg.PreventClip {
+		clip = "yes"
+	}
+	return m.SetPropertyString("replaygain-clip", clip)
+}
+
+// ApplyReplayGain 在文件加载后调用：计算实际应用的增益，缺少标签时按设置启用 loudnorm。
+// tagged 为 false 表示当前文件没有 ReplayGain 标签
+func (m *Mpvplayer) ApplyReplayGain(rg ReplayGain) (gain float64, tagged bool, err error) {
+	if rg.Mode == ReplayGainOff || rg.Mode == "" {
+		return 0, false, m.SetFilter("loudnorm", "")
+	}
+
+	gain, peak, tagged := m.replayGainTags(rg.Mode == ReplayGainAlbum)
+	if !tagged {
+		filter := ""
+		if rg.Loudnorm {
+			filter = loudnormFilter
+		}
+		return 0, false, m.SetFilter("loudnorm", filter)
+	}
+
+	gain += rg.Preamp
+	if rg.PreventClip && peak > 0 {
+		gain = min(gain, -20*math.Log10(peak))
+	}
+	return gain, true, m.SetFilter("loudnorm", "")
+}
+
+// replayGainTags 读取当前音轨的 ReplayGain 标签，专辑增益缺失时和 mpv 一样退回音轨增益
+func (m *Mpvplayer) replayGainTags(album bool) (gain, peak float64, ok bool) {
+	get := func(name string) (float64, bool) {
+		value, err := m.GetProperty("current-tracks/audio/"+name, mpv.FORMAT_DOUBLE)
+		if err != nil {
+			return 0, false
+		}
+		v, ok := value.(float64)
+		return v, ok
+	}
+
+	if album {
+		if gain, ok := get("replaygain-album-gain"); ok {
+			peak, _ := get("replaygain-album-peak")
+			return gain, peak, true
+		}
+	}
+	if gain, ok := get("replaygain-track-gain"); ok {
+		peak, _ := get("replaygain-track-peak")
+		return gain, peak, true
+	}
+	return 0, 0, false
+}
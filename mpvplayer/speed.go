@@ -0,0 +1,36 @@
+package mpvplayer
+
+import (
+	"math"
+
+	"github.com/wildeyedskies/go-mpv/mpv"
+)
+
+// 播放速度范围
+const (
+	MinSpeed = 0.5
+	MaxSpeed = 3.0
+)
+
+// SetSpeed 设置播放速度，keepPitch 为 true 时用 scaletempo2 保持音调。
+// 速度限制在 MinSpeed 到 MaxSpeed 之间并取整到 0.05，返回实际设置的速度
+func (m *Mpvplayer) SetSpeed(speed float64, keepPitch bool) (float64, error) {
+	speed = math.Round(max(min(speed, MaxSpeed), MinSpeed)*20) / 20
+
+	tempo := ""
+	if keepPitch && speed != 1 {
+		tempo = "scaletempo2"
+	}
+	if err := m.SetFilter("tempo", tempo); err != nil {
+		return speed, err
+	}
+	// 不保持音调时关闭 mpv 自动插入的 scaletempo2
+	pitchCorrection := "no"
+	if keepPitch {
+		pitchCorrection = "yes"
+	}
+	if err := m.SetPropertyString("audio-pitch-correction", pitchCorrection); err != nil {
+		return speed, err
+	}
+	return speed, m.SetProperty("speed", mpv.FORMAT_DOUBLE, speed)
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/spf13/viper"
+	"github.com/yhkl-dev/NaviCLI/subsonic"
+)
+
+// viewGuide 首次运行时介绍的主要视图，按键从动作表中查找
+var viewGuide = []struct {
+	action string
+	text   string
+}{
+	{"reload", "Random mix: the list you start with, re-rolled with this key"},
+	{"mix", "Mix builder: random songs filtered by genre, year range and library"},
+	{"albums", "Album lists: newest, recently played, frequent, random and A-Z"},
+	{"find", "Library search on the server"},
+	{"radio", "Radio: keeps the queue going with songs similar to the selected one"},
+	{"stations", "Internet radio stations stored on the server"},
+	{"podcasts", "Podcast channels and episodes"},
+	{"bookmarks", "Resume long tracks and audiobooks where you stopped"},
+	{"queue", "Play queue of marked tracks"},
+	{"stats", "Local listening history and statistics"},
+}
+
+// runOnboarding 首次运行时填写服务器信息，连接验证通过后写入配置文件并介绍主要视图。
+// 用户放弃时返回错误
+func runOnboarding() error {
+	app := tview.NewApplication()
+	done := false
+
+	status := tview.NewTextView().SetDynamicColors(true)
+	status.SetText("[gray]Enter your Navidrome / Subsonic server to get started")
+
+	form := tview.NewForm().
+		AddInputField("Server URL", viper.GetString("server.url"), 50, nil, nil).
+		AddInputField("Username", viper.GetString("server.username"), 50, nil, nil).
+		AddPasswordField("Password", viper.GetString("server.password"), 50, '*', nil)
+	form.SetBorder(true).SetTitle(" Welcome to NaviCLI ")
+
+	text := func(label string) string {
+		return strings.TrimSpace(form.GetFormItemByLabel(label).(*tview.InputField).GetText())
+	}
+
+	layout := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(form, 11, 0, true).
+		AddItem(status, 2, 0, false)
+
+	form.AddButton("Connect", func() {
+		url, username, password := text("Server URL"), text("Username"), text("Password")
+		if url == "" || username == "" || password == "" {
+			status.SetText("[red]Server URL, username and password are required")
+			return
+		}
+		status.SetText("[yellow]Connecting to " + tview.Escape(url) + "...")
+
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			defer cancel()
+
+			client := subsonic.Init(url, username, password, "goplayer", subsonic.DefaultAPIVersion)
+			info, err := client.Negotiate(ctx)
+			if err != nil {
+				app.QueueUpdateDraw(func() {
+					status.SetText("[red]connect failed: " + tview.Escape(err.Error()))
+				})
+				return
+			}
+
+			path, err := saveConfig(map[string]any{
+				"server.url":      url,
+				"server.username": username,
+				"server.password": password,
+			})
+			app.QueueUpdateDraw(func() {
+				if err != nil {
+					status.SetText("[red]save config failed: " + tview.Escape(err.Error()))
+					return
+				}
+				app.SetRoot(viewsIntro(info, path, func() {
+					done = true
+					app.Stop()
+				}), true)
+			})
+		}()
+	})
+	form.AddButton("Quit", func() {
+		app.Stop()
+	})
+
+	if err := app.SetRoot(layout, true).Run(); err != nil {
+		return err
+	}
+	if !done {
+		return fmt.Errorf("setup cancelled")
+	}
+	return nil
+}
+
+// viewsIntro 连接成功后介绍主要视图，Enter 进入主界面
+func viewsIntro(info *subsonic.ServerInfo, configPath string, onDone func()) tview.Primitive {
+	// 只用于查找按键（包括配置覆盖的按键），不会执行任何动作
+	actions := new(Application)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[lightgreen]Connected to %s %s\n", tview.Escape(info.Type), tview.Escape(info.ServerVersion))
+	fmt.Fprintf(&b, "[darkgray]Settings saved to %s\n\n", tview.Escape(configPath))
+	b.WriteString("[yellow]Views\n")
+	for _, view := range viewGuide {
+		key := ""
+		if act, ok := actions.findAction(view.action); ok && len(act.Keys) > 0 {
+			key = act.Keys[0]
+		}
+		fmt.Fprintf(&b, "[lightgreen]%-4s [-]%s\n", tview.Escape(key), view.text)
+	}
+	b.WriteString("\n[gray]Press [lightgreen]?[gray] any time for all keys, [lightgreen]:[gray] for the command line.\n")
+	b.WriteString("[gray]Press [lightgreen]Enter[gray] to start.")
+
+	view := tview.NewTextView().SetDynamicColors(true).SetText(b.String())
+	view.SetBorder(true).SetTitle(" Getting around ")
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEnter || event.Key() == tcell.KeyEsc {
+			onDone()
+			return nil
+		}
+		return event
+	})
+	return view
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/rivo/tview"
+	"github.com/yhkl-dev/NaviCLI/subsonic"
+)
+
+// songSource 分页获取服务器端的歌曲列表，offset 为已加载的歌曲数
+type songSource func(ctx context.Context, offset, size int) ([]subsonic.Song, error)
+
+// loadSource 加载服务器端列表的第一页，翻到最后一页时再获取后续歌曲
+func (a *Application) loadSource(view string, src songSource) error {
+	ctx := a.beginRefresh()
+	songs, err := src(ctx, 0, a.pageSize)
+	if ctx.Err() != nil {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	a.stopRadio()
+	a.setSongs(view, songs, src, ctx, len(songs) < a.pageSize)
+	return nil
+}
+
+// replaceSongs 替换当前歌曲列表并重新渲染表格，view 决定使用的列和排序
+func (a *Application) replaceSongs(view string, songs []subsonic.Song) {
+	a.setSongs(view, songs, nil, nil, true)
+}
+
+// setSongs 在 UI 线程替换列表并等待完成，返回后调用方可以按位置播放新列表中的歌曲。
+// 只能在后台 goroutine 中调用
+func (a *Application) setSongs(view string, songs []subsonic.Song, src songSource, ctx context.Context, done bool) {
+	a.noteSongs(songs)
+	a.syncUpdate(func() {
+		a.loadingMux.Lock()
+		a.source = src
+		a.sourceCtx = ctx
+		a.sourceDone = done
+		a.loadingMux.Unlock()
+
+		a.view = view
+		a.baseSongs = songs
+		a.filter = ""
+		a.marked = nil
+		a.markAnchor = -1
+		a.message = ""
+		a.currentPage = 1
+		a.applyView()
+		a.renderSongTable()
+	})
+}
+
+// syncUpdate 在 UI 线程执行 f 并等待其完成，程序退出后不再等待。
+// 在 UI 线程中调用会死锁
+func (a *Application) syncUpdate(f func()) {
+	done := make(chan struct{})
+	a.application.QueueUpdateDraw(func() {
+		f()
+		close(done)
+	})
+	select {
+	case <-done:
+	case <-a.ctx.Done():
+	}
+}
+
+// hasMore 当前列表在服务器端是否还有未加载的歌曲
+func (a *Application) hasMore() bool {
+	a.loadingMux.Lock()
+	defer a.loadingMux.Unlock()
+	return a.source != nil && !a.sourceDone
+}
+
+// fetchMore 获取服务器端列表的下一批歌曲，advance 为 true 时加载完成后翻到下一页
+func (a *Application) fetchMore(advance bool) {
+	a.loadingMux.Lock()
+	if a.source == nil || a.sourceDone || a.fetchingMore {
+		a.loadingMux.Unlock()
+		return
+	}
+	a.fetchingMore = true
+	src, ctx, offset := a.source, a.sourceCtx, len(a.baseSongs)
+	a.loadingMux.Unlock()
+
+	songs, err := src(ctx, offset, a.pageSize)
+
+	a.loadingMux.Lock()
+	a.fetchingMore = false
+	// 列表已被替换
+	if ctx.Err() != nil || a.sourceCtx != ctx {
+		a.loadingMux.Unlock()
+		return
+	}
+	if err != nil {
+		a.loadingMux.Unlock()
+		log.Println("fetch more songs failed:", err)
+		return
+	}
+	a.sourceDone = len(songs) < a.pageSize
+	a.loadingMux.Unlock()
+	a.noteSongs(songs)
+
+	a.application.QueueUpdateDraw(func() {
+		a.loadingMux.Lock()
+		replaced := a.sourceCtx != ctx
+		a.loadingMux.Unlock()
+		if replaced {
+			return
+		}
+
+		a.baseSongs = append(a.baseSongs, songs...)
+		a.applyView()
+		if advance && a.currentPage < a.totalPages {
+			a.setPage(a.currentPage + 1)
+			return
+		}
+		a.renderSongTable()
+	})
+}
+
+// setPage 切换到指定页，需要在 UI 线程调用
+func (a *Application) setPage(page int) {
+	page = max(min(page, a.totalPages), 1)
+	if page != a.currentPage {
+		a.currentPage = page
+		a.renderSongTable()
+	}
+	if a.songTable.GetRowCount() > 1 {
+		a.songTable.Select(1, 0)
+	}
+
+	// 到达最后一页时预先获取下一批
+	if page == a.totalPages && a.hasMore() {
+		go a.fetchMore(false)
+	}
+}
+
+func (a *Application) nextPage() {
+	if a.currentPage < a.totalPages {
+		a.setPage(a.currentPage + 1)
+		return
+	}
+	go a.fetchMore(true)
+}
+
+func (a *Application) previousPage() {
+	a.setPage(a.currentPage - 1)
+}
+
+// songIndex 将表格行号转换为歌曲列表中的下标
+func (a *Application) songIndex(row int) int {
+	return (a.currentPage-1)*a.pageSize + row - 1
+}
+
+// showSong 切换到歌曲所在页并选中，需要在 UI 线程调用
+func (a *Application) showSong(index int) {
+	if index < 0 || index >= len(a.totalSongs) {
+		return
+	}
+	if page := index/a.pageSize + 1; page != a.currentPage {
+		a.currentPage = page
+		a.renderSongTable()
+	}
+	a.songTable.Select(index-(a.currentPage-1)*a.pageSize+1, 0)
+}
+
+// pageIndicator 表格下方显示的页码和排序，服务器端还有更多歌曲时加上 "+"
+func (a *Application) pageIndicator() string {
+	more := ""
+	if a.hasMore() {
+		more = "+"
+	}
+	extra := sortText(a.layout().Sort)
+	if a.filter != "" {
+		extra += " · filter: " + tview.Escape(a.filter)
+	}
+	if len(a.marked) > 0 {
+		extra += fmt.Sprintf(" · [yellow]%d marked[darkgray]", len(a.marked))
+	}
+	if queued := len(a.queuedSongs()); queued > 0 {
+		extra += fmt.Sprintf(" · %d queued", queued)
+	}
+	if a.message != "" {
+		extra += " · " + a.message
+	}
+	return fmt.Sprintf("[darkgray]%s · Page %d/%d%s · %d%s songs%s",
+		a.view, a.currentPage, max(a.totalPages, 1), more, len(a.totalSongs), more, extra)
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/yhkl-dev/NaviCLI/subsonic"
+)
+
+const newestEpisodeCount = 50
+
+// episodeSong 将已下载的播客节目转换为可播放的歌曲
+func episodeSong(episode subsonic.PodcastEpisode, channel string) subsonic.Song {
+	return subsonic.Song{
+		ID:          episode.StreamID,
+		Title:       episode.Title,
+		Artist:      channel,
+		Album:       channel,
+		Duration:    episode.Duration,
+		Size:        episode.Size,
+		Suffix:      episode.Suffix,
+		ContentType: episode.ContentType,
+		Type:        "podcast",
+	}
+}
+
+func episodeStatusColor(status string) string {
+	switch status {
+	case "completed":
+		return "lightgreen"
+	case "downloading":
+		return "yellow"
+	case "error":
+		return "red"
+	}
+	return "darkgray"
+}
+
+// showPodcasts 打开播客频道列表：Enter 查看节目，a 订阅频道，r 刷新全部频道
+func (a *Application) showPodcasts() {
+	go func() {
+		channels, err := a.subsonicClient.GetPodcastsContext(a.ctx, false)
+		if err != nil {
+			a.application.QueueUpdateDraw(func() {
+				a.statusBar.SetText("[red]load podcasts failed: " + err.Error())
+			})
+			return
+		}
+		a.application.QueueUpdateDraw(func() {
+			a.podcastsUI(channels)
+		})
+	}()
+}
+
+func (a *Application) podcastsUI(channels []subsonic.PodcastChannel) {
+	list := tview.NewList()
+	list.SetBorder(true).SetTitle(" Podcasts (Enter open, a subscribe, r refresh) ")
+	list.AddItem("[yellow]Newest episodes", "", 0, nil)
+	for _, channel := range channels {
+		list.AddItem(tview.Escape(channel.Title), "[darkgray]"+tview.Escape(channel.URL), 0, nil)
+	}
+
+	channelTitles := make(map[string]string, len(channels))
+	for _, channel := range channels {
+		channelTitles[channel.ID] = channel.Title
+	}
+
+	list.SetSelectedFunc(func(index int, _, _ string, _ rune) {
+		go func() {
+			var title string
+			var episodes []subsonic.PodcastEpisode
+			var err error
+			if index == 0 {
+				title = "Newest episodes"
+				episodes, err = a.subsonicClient.GetNewestPodcastsContext(a.ctx, newestEpisodeCount)
+			} else {
+				var channel *subsonic.PodcastChannel
+				channel, err = a.subsonicClient.GetPodcastChannelContext(a.ctx, channels[index-1].ID)
+				if err == nil {
+					title = channel.Title
+					episodes = channel.Episodes
+				}
+			}
+			a.application.QueueUpdateDraw(func() {
+				if err != nil {
+					a.closeOverlay()
+					a.statusBar.SetText("[red]load podcast episodes failed: " + err.Error())
+					return
+				}
+				a.episodesUI(title, episodes, channelTitles)
+			})
+		}()
+	})
+
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'a':
+			a.subscribeForm()
+			return nil
+		case 'r':
+			go func() {
+				if err := a.subsonicClient.RefreshPodcastsContext(a.ctx); err != nil {
+					a.application.QueueUpdateDraw(func() {
+						a.statusBar.SetText("[red]refresh podcasts failed: " + err.Error())
+					})
+					return
+				}
+				a.showPodcasts()
+			}()
+			return nil
+		}
+		return event
+	})
+
+	a.showOverlay("podcasts", list, 80, 24)
+}
+
+// episodesUI 节目列表：Enter 播放（未下载时先下载），d 下载，x 删除，Backspace 返回频道列表
+func (a *Application) episodesUI(title string, episodes []subsonic.PodcastEpisode, channelTitles map[string]string) {
+	list := tview.NewList()
+	list.SetBorder(true).SetTitle(fmt.Sprintf(" %s (Enter play, d download, x delete) ", tview.Escape(title)))
+	for _, episode := range episodes {
+		color := episodeStatusColor(episode.Status)
+		list.AddItem(
+			fmt.Sprintf("[%s]%s", color, tview.Escape(episode.Title)),
+			fmt.Sprintf("[darkgray]%s  %s  %s",
+				episode.PublishDate.Format("2006-01-02"),
+				formatDuration(episode.Duration),
+				episode.Status),
+			0, nil)
+	}
+	if len(episodes) == 0 {
+		list.AddItem("[darkgray]No episodes", "", 0, nil)
+	}
+
+	list.SetSelectedFunc(func(index int, _, _ string, _ rune) {
+		if index >= len(episodes) {
+			return
+		}
+		if episodes[index].Status != "completed" {
+			a.downloadEpisode(episodes[index])
+			return
+		}
+
+		// 将已下载的节目放入播放列表，从选中的节目开始播放
+		songs := make([]subsonic.Song, 0, len(episodes))
+		start := 0
+		for i, episode := range episodes {
+			if episode.Status != "completed" {
+				continue
+			}
+			if i == index {
+				start = len(songs)
+			}
+			songs = append(songs, episodeSong(episode, channelTitles[episode.ChannelID]))
+		}
+
+		a.closeOverlay()
+		a.stopRadio()
+		go func() {
+			a.replaceSongs("podcast", songs)
+			a.playSongAtIndex(a.indexOf(songs[start].ID))
+		}()
+	})
+
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		index := list.GetCurrentItem()
+		if event.Key() == tcell.KeyBackspace || event.Key() == tcell.KeyBackspace2 {
+			a.showPodcasts()
+			return nil
+		}
+		switch event.Rune() {
+		case 'd':
+			if index < len(episodes) {
+				a.downloadEpisode(episodes[index])
+			}
+			return nil
+		case 'x':
+			if index < len(episodes) {
+				a.deleteEpisode(episodes[index])
+			}
+			return nil
+		}
+		return event
+	})
+
+	a.showOverlay("episodes", list, 90, 28)
+}
+
+func (a *Application) downloadEpisode(episode subsonic.PodcastEpisode) {
+	go func() {
+		err := a.subsonicClient.DownloadPodcastEpisodeContext(a.ctx, episode.ID)
+		a.application.QueueUpdateDraw(func() {
+			if err != nil {
+				a.statusBar.SetText("[red]download episode failed: " + err.Error())
+				return
+			}
+			a.statusBar.SetText("[yellow]Downloading on server:\n[-]" + tview.Escape(episode.Title))
+		})
+	}()
+}
+
+func (a *Application) deleteEpisode(episode subsonic.PodcastEpisode) {
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Delete episode %q?", episode.Title)).
+		AddButtons([]string{"Delete", "Cancel"}).
+		SetDoneFunc(func(_ int, label string) {
+			if label != "Delete" {
+				a.showPodcasts()
+				return
+			}
+			go func() {
+				if err := a.subsonicClient.DeletePodcastEpisodeContext(a.ctx, episode.ID); err != nil {
+					a.application.QueueUpdateDraw(func() {
+						a.closeOverlay()
+						a.statusBar.SetText("[red]delete episode failed: " + err.Error())
+					})
+					return
+				}
+				a.showPodcasts()
+			}()
+		})
+
+	a.showOverlay("episode-delete", modal, 60, 7)
+}
+
+func (a *Application) subscribeForm() {
+	form := tview.NewForm().
+		AddInputField("Feed URL", "", 50, nil, nil)
+	form.SetBorder(true).SetTitle(" Subscribe ")
+
+	form.AddButton("Subscribe", func() {
+		url := form.GetFormItemByLabel("Feed URL").(*tview.InputField).GetText()
+		if url == "" {
+			return
+		}
+		go func() {
+			if err := a.subsonicClient.CreatePodcastChannelContext(a.ctx, url); err != nil {
+				a.application.QueueUpdateDraw(func() {
+					a.closeOverlay()
+					a.statusBar.SetText("[red]subscribe podcast failed: " + err.Error())
+				})
+				return
+			}
+			a.showPodcasts()
+		}()
+	})
+	form.AddButton("Cancel", func() {
+		a.showPodcasts()
+	})
+
+	a.showOverlay("podcast-subscribe", form, 70, 7)
+}
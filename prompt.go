@@ -0,0 +1,49 @@
+package main
+
+import (
+	"github.com/rivo/tview"
+)
+
+// showPrompt 用输入框替换表格下方的状态行，上方一行显示提示。
+// 输入框打开时按键交给输入框处理，Esc 关闭并调用 onCancel
+func (a *Application) showPrompt(name string, input *tview.InputField, onCancel func()) {
+	if a.promptInput != nil {
+		a.closePrompt()
+	}
+
+	a.searchMux.Lock()
+	a.overlay = name
+	a.searchMux.Unlock()
+
+	a.promptHint = tview.NewTextView().SetDynamicColors(true)
+	a.promptInput = input
+	a.promptCancel = onCancel
+
+	a.rightPanel.RemoveItem(a.pageBar)
+	a.rightPanel.AddItem(a.promptHint, 1, 0, false)
+	a.rightPanel.AddItem(input, 1, 0, true)
+	a.application.SetFocus(input)
+}
+
+// setPromptHint 在输入框上方显示提示，如补全候选
+func (a *Application) setPromptHint(text string) {
+	if a.promptHint != nil {
+		a.promptHint.SetText(text)
+	}
+}
+
+// closePrompt 关闭输入框并恢复状态行
+func (a *Application) closePrompt() {
+	a.searchMux.Lock()
+	a.overlay = ""
+	a.searchMux.Unlock()
+
+	if a.promptInput != nil {
+		a.rightPanel.RemoveItem(a.promptHint)
+		a.rightPanel.RemoveItem(a.promptInput)
+		a.rightPanel.AddItem(a.pageBar, 1, 0, false)
+		a.promptInput, a.promptHint, a.promptCancel = nil, nil, nil
+	}
+	a.pageBar.SetText(a.pageIndicator())
+	a.application.SetFocus(a.songTable)
+}
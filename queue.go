@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/yhkl-dev/NaviCLI/subsonic"
+)
+
+// enqueue 将歌曲追加到播放队列末尾，队列中的歌曲在列表的下一首之前播放
+func (a *Application) enqueue(songs []subsonic.Song) {
+	a.loadingMux.Lock()
+	a.queue = append(a.queue, songs...)
+	a.loadingMux.Unlock()
+}
+
+// playNext 将歌曲插入播放队列开头，当前歌曲结束后立即播放
+func (a *Application) playNext(songs []subsonic.Song) {
+	a.loadingMux.Lock()
+	a.queue = append(slices.Clone(songs), a.queue...)
+	a.loadingMux.Unlock()
+}
+
+func (a *Application) popQueue() (subsonic.Song, bool) {
+	a.loadingMux.Lock()
+	defer a.loadingMux.Unlock()
+	if len(a.queue) == 0 {
+		return subsonic.Song{}, false
+	}
+	song := a.queue[0]
+	a.queue = a.queue[1:]
+	return song, true
+}
+
+func (a *Application) clearQueue() {
+	a.loadingMux.Lock()
+	a.queue = nil
+	a.loadingMux.Unlock()
+}
+
+func (a *Application) queuedSongs() []subsonic.Song {
+	a.loadingMux.Lock()
+	defer a.loadingMux.Unlock()
+	return slices.Clone(a.queue)
+}
+
+// showQueue 打开播放队列：Enter 立即播放，x 移出队列，c 清空
+func (a *Application) showQueue() {
+	a.queueUI(0)
+}
+
+func (a *Application) queueUI(current int) {
+	songs := a.queuedSongs()
+	list := tview.NewList()
+	list.SetBorder(true).SetTitle(fmt.Sprintf(" Queue: %d songs (Enter play, x remove, c clear) ", len(songs)))
+	for _, song := range songs {
+		list.AddItem(fmt.Sprintf("%s [gray]- %s", tview.Escape(song.Title), tview.Escape(song.Artist)),
+			"[darkgray]"+tview.Escape(song.Album)+"  "+formatDuration(song.Duration), 0, nil)
+	}
+	if len(songs) == 0 {
+		list.AddItem("[darkgray]Queue is empty", "[darkgray]mark songs with v and press A to enqueue", 0, nil)
+	}
+	list.SetCurrentItem(current)
+
+	// remove 按位置移出队列，队列已被修改时按歌曲 ID 查找
+	remove := func(index int) (subsonic.Song, bool) {
+		a.loadingMux.Lock()
+		defer a.loadingMux.Unlock()
+		i := slices.IndexFunc(a.queue, func(s subsonic.Song) bool { return s.ID == songs[index].ID })
+		if i < 0 {
+			return subsonic.Song{}, false
+		}
+		song := a.queue[i]
+		a.queue = slices.Delete(a.queue, i, i+1)
+		return song, true
+	}
+
+	list.SetSelectedFunc(func(index int, _, _ string, _ rune) {
+		if index >= len(songs) {
+			return
+		}
+		song, ok := remove(index)
+		if !ok {
+			return
+		}
+		a.closeOverlay()
+		target := a.indexOf(song.ID)
+		if target < 0 {
+			target = a.currentSongIndex
+		}
+		go a.playSong(song, target)
+	})
+
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		index := list.GetCurrentItem()
+		switch event.Rune() {
+		case 'x':
+			if index < len(songs) {
+				remove(index)
+				a.queueUI(max(index-1, 0))
+			}
+			return nil
+		case 'c':
+			a.clearQueue()
+			a.queueUI(0)
+			return nil
+		}
+		return event
+	})
+
+	a.showOverlay("queue", list, 80, 24)
+}
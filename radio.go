@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/yhkl-dev/NaviCLI/subsonic"
+)
+
+// 队列剩余歌曲少于该数量时自动补充
+const radioLowWater = 3
+
+func (a *Application) isRadioMode() bool {
+	a.loadingMux.Lock()
+	defer a.loadingMux.Unlock()
+	return a.radioMode
+}
+
+func (a *Application) stopRadio() {
+	a.loadingMux.Lock()
+	a.radioMode = false
+	a.loadingMux.Unlock()
+}
+
+// selectedSong 返回表格中当前选中的歌曲
+func (a *Application) selectedSong() (subsonic.Song, bool) {
+	row, _ := a.songTable.GetSelection()
+	index := a.songIndex(row)
+	if row <= 0 || index >= len(a.totalSongs) {
+		return subsonic.Song{}, false
+	}
+	return a.totalSongs[index], true
+}
+
+// toggleRadio 开启或关闭电台模式，开启时以正在播放的歌曲为种子
+func (a *Application) toggleRadio() {
+	if a.isRadioMode() {
+		a.stopRadio()
+		return
+	}
+
+	a.loadingMux.Lock()
+	current := a.currentSong
+	a.loadingMux.Unlock()
+
+	if current != nil {
+		go a.startRadio(*current)
+	} else if song, ok := a.selectedSong(); ok {
+		go a.startRadio(song)
+	}
+}
+
+// startRadio 以 seed 为种子生成电台队列并开始播放
+func (a *Application) startRadio(seed subsonic.Song) {
+	ctx := a.beginRefresh()
+	songs, err := a.radioCandidates(ctx, seed, map[string]bool{seed.ID: true})
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		a.application.QueueUpdateDraw(func() {
+			a.statusBar.SetText("[red]start radio failed: " + err.Error())
+		})
+		return
+	}
+
+	a.loadingMux.Lock()
+	a.radioMode = true
+	a.loadingMux.Unlock()
+
+	a.replaceSongs("radio", append([]subsonic.Song{seed}, songs...))
+	a.playSongAtIndex(a.indexOf(seed.ID))
+}
+
+// topUpRadio 以当前歌曲为种子向队列末尾追加新歌曲，在 UI 线程修改列表并等待完成。
+// 已有补充在进行时等待它结束后返回
+func (a *Application) topUpRadio() error {
+	a.loadingMux.Lock()
+	if wait := a.radioFetching; wait != nil {
+		a.loadingMux.Unlock()
+		<-wait
+		return nil
+	}
+	if a.currentSong == nil {
+		a.loadingMux.Unlock()
+		return nil
+	}
+	fetching := make(chan struct{})
+	a.radioFetching = fetching
+	seed := *a.currentSong
+	a.loadingMux.Unlock()
+
+	defer func() {
+		a.loadingMux.Lock()
+		a.radioFetching = nil
+		a.loadingMux.Unlock()
+		close(fetching)
+	}()
+
+	var exclude map[string]bool
+	a.syncUpdate(func() {
+		exclude = make(map[string]bool, len(a.baseSongs))
+		for _, song := range a.baseSongs {
+			exclude[song.ID] = true
+		}
+	})
+
+	songs, err := a.radioCandidates(a.ctx, seed, exclude)
+	if err != nil {
+		return err
+	}
+	if !a.isRadioMode() {
+		return nil
+	}
+
+	a.noteSongs(songs)
+	a.syncUpdate(func() {
+		a.baseSongs = append(a.baseSongs, songs...)
+		a.applyView()
+		a.renderSongTable()
+		a.showSong(a.currentSongIndex)
+	})
+	return nil
+}
+
+// radioCandidates 从相似歌曲、热门歌曲和同流派随机歌曲中挑选候选，
+// 跳过 exclude 中的歌曲以及最近 radio.avoid_hours 小时内播放过的歌曲
+func (a *Application) radioCandidates(ctx context.Context, seed subsonic.Song, exclude map[string]bool) ([]subsonic.Song, error) {
+	batchSize := viper.GetInt("radio.batch_size")
+
+	if a.history != nil {
+		since := time.Now().Add(-time.Duration(viper.GetInt("radio.avoid_hours")) * time.Hour)
+		for _, p := range a.history.Plays(since) {
+			exclude[p.SongID] = true
+		}
+	}
+
+	var candidates []subsonic.Song
+	var firstErr error
+	collect := func(songs []subsonic.Song, err error) {
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			return
+		}
+		for _, song := range songs {
+			if !exclude[song.ID] {
+				exclude[song.ID] = true
+				candidates = append(candidates, song)
+			}
+		}
+	}
+
+	collect(a.subsonicClient.GetSimilarSongsContext(ctx, seed.ID, batchSize))
+	if seed.ArtistID != "" {
+		collect(a.subsonicClient.GetSimilarSongs2Context(ctx, seed.ArtistID, batchSize))
+	}
+	if seed.Artist != "" {
+		collect(a.subsonicClient.GetTopSongsContext(ctx, seed.Artist, batchSize/2))
+	}
+	collect(a.subsonicClient.GetRandomSongsContext(ctx, subsonic.RandomSongsOptions{
+		Size:  batchSize,
+		Genre: seed.Genre,
+	}))
+
+	// 同流派歌曲都已播放过时退回到全库随机
+	if len(candidates) == 0 {
+		collect(a.subsonicClient.GetRandomSongsContext(ctx, subsonic.RandomSongsOptions{Size: batchSize}))
+	}
+
+	if len(candidates) == 0 {
+		if firstErr != nil {
+			return nil, firstErr
+		}
+		return nil, fmt.Errorf("no radio candidates for %s", seed.Title)
+	}
+
+	rand.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	if len(candidates) > batchSize {
+		candidates = candidates[:batchSize]
+	}
+	return candidates, nil
+}
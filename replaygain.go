@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/yhkl-dev/NaviCLI/mpvplayer"
+)
+
+var replayGainModes = []string{mpvplayer.ReplayGainOff, mpvplayer.ReplayGainTrack, mpvplayer.ReplayGainAlbum}
+
+// loadReplayGain 读取配置 replaygain.* 并应用
+func (a *Application) loadReplayGain() {
+	rg := mpvplayer.ReplayGain{
+		Mode:        viper.GetString("replaygain.mode"),
+		Preamp:      viper.GetFloat64("replaygain.preamp"),
+		PreventClip: viper.GetBool("replaygain.prevent_clip"),
+		Loudnorm:    viper.GetBool("replaygain.loudnorm_fallback"),
+	}
+	if !slices.Contains(replayGainModes, rg.Mode) {
+		log.Println("unknown replaygain mode:", rg.Mode)
+		rg.Mode = mpvplayer.ReplayGainOff
+	}
+	a.applyReplayGain(rg, false)
+}
+
+// applyReplayGain 应用音量标准化设置，save 为 true 时写回配置
+func (a *Application) applyReplayGain(rg mpvplayer.ReplayGain, save bool) {
+	a.loadingMux.Lock()
+	a.replayGain = rg
+	a.loadingMux.Unlock()
+
+	if a.mpvInstance != nil && a.mpvInstance.Mpv != nil {
+		if err := a.mpvInstance.SetReplayGain(rg); err != nil {
+			log.Println("apply replaygain failed:", err)
+		}
+	}
+	if !save {
+		return
+	}
+
+	err := saveSettings(map[string]any{
+		"replaygain.mode":              rg.Mode,
+		"replaygain.preamp":            rg.Preamp,
+		"replaygain.prevent_clip":      rg.PreventClip,
+		"replaygain.loudnorm_fallback": rg.Loudnorm,
+	})
+	if err != nil {
+		log.Println("save replaygain failed:", err)
+	}
+}
+
+// onFileLoaded mpv 加载完文件后检查 ReplayGain 标签，记录实际增益供播放面板显示
+func (a *Application) onFileLoaded() {
+	if a.mpvInstance == nil || a.mpvInstance.Mpv == nil {
+		return
+	}
+	a.loadingMux.Lock()
+	rg := a.replayGain
+	a.loadingMux.Unlock()
+
+	gain, tagged, err := a.mpvInstance.ApplyReplayGain(rg)
+	if err != nil {
+		log.Println("apply replaygain failed:", err)
+	}
+
+	var info string
+	switch {
+	case rg.Mode == mpvplayer.ReplayGainOff:
+	case tagged:
+		info = fmt.Sprintf("[darkgray][gain] %s %+.1f dB", rg.Mode, gain)
+	case rg.Loudnorm:
+		info = "[darkgray][gain] loudnorm (no tags)"
+	default:
+		info = "[darkgray][gain] no tags"
+	}
+
+	a.loadingMux.Lock()
+	a.gainInfo = info
+	a.loadingMux.Unlock()
+}
+
+// replayGainCommand 无参数时在 off、track、album 之间切换，
+// 也可以设置模式或 preamp <dB>、clip on|off、loudnorm on|off
+func (a *Application) replayGainCommand(arg string) error {
+	a.loadingMux.Lock()
+	rg := a.replayGain
+	a.loadingMux.Unlock()
+
+	sub, value, _ := strings.Cut(arg, " ")
+	value = strings.TrimSpace(value)
+	switch sub {
+	case "":
+		rg.Mode = replayGainModes[(slices.Index(replayGainModes, rg.Mode)+1)%len(replayGainModes)]
+	case mpvplayer.ReplayGainOff, mpvplayer.ReplayGainTrack, mpvplayer.ReplayGainAlbum:
+		rg.Mode = sub
+	case "preamp":
+		preamp, err := strconv.ParseFloat(value, 64)
+		if err != nil || preamp < -15 || preamp > 15 {
+			return fmt.Errorf("invalid preamp: %q (-15 to 15 dB)", value)
+		}
+		rg.Preamp = preamp
+	case "clip":
+		on, err := parseOnOff(value)
+		if err != nil {
+			return err
+		}
+		rg.PreventClip = on
+	case "loudnorm":
+		on, err := parseOnOff(value)
+		if err != nil {
+			return err
+		}
+		rg.Loudnorm = on
+	default:
+		return fmt.Errorf("unknown replaygain setting: %s", sub)
+	}
+
+	a.applyReplayGain(rg, true)
+	a.setMessage(fmt.Sprintf("[lightgreen]replaygain %s, preamp %+.1f dB, clip prevention %s, loudnorm fallback %s [darkgray](next track)",
+		rg.Mode, rg.Preamp, onOff(rg.PreventClip), onOff(rg.Loudnorm)))
+	return nil
+}
+
+func parseOnOff(value string) (bool, error) {
+	switch value {
+	case "on", "yes", "true":
+		return true, nil
+	case "off", "no", "false":
+		return false, nil
+	}
+	return false, fmt.Errorf("expected on or off: %q", value)
+}
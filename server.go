@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/rivo/tview"
+	"github.com/spf13/viper"
+	"github.com/yhkl-dev/NaviCLI/streamproxy"
+	"github.com/yhkl-dev/NaviCLI/subsonic"
+)
+
+// newClient 按配置前缀（server 或 servers.<name>）创建客户端
+func newClient(prefix string) *subsonic.Client {
+	client := subsonic.Init(
+		viper.GetString(prefix+".url"),
+		viper.GetString(prefix+".username"),
+		viper.GetString(prefix+".password"),
+		"goplayer",
+		subsonic.DefaultAPIVersion,
+	)
+	client.APIKey = viper.GetString(prefix + ".api_key")
+	return client
+}
+
+// serverPrefixFor 服务器名称对应的配置前缀，default 为 [server]
+func serverPrefixFor(name string) string {
+	if name == "" || name == "default" {
+		return "server"
+	}
+	return "servers." + name
+}
+
+func (a *Application) serverCommand(name string) error {
+	if name == "" {
+		a.setMessage("[lightgreen]servers: " + tview.Escape(strings.Join(serverNames(), ", ")))
+		return nil
+	}
+	prefix := serverPrefixFor(name)
+	if !viper.IsSet(prefix + ".url") {
+		return fmt.Errorf("unknown server: %s", name)
+	}
+	go a.switchServer(name, prefix)
+	return nil
+}
+
+// switchServer 连接到另一台服务器，停止当前播放并重新加载列表
+func (a *Application) switchServer(name, prefix string) {
+	a.setMessage("[yellow]connecting to " + name + "...")
+	client := newClient(prefix)
+	if _, err := client.Negotiate(a.ctx); err != nil {
+		a.setMessage("[red]connect to " + name + " failed: " + err.Error())
+		return
+	}
+
+	// 先记录当前播放，书签需要写回原来的服务器
+	a.recordPlay(playStopped)
+	a.loadingMux.Lock()
+	if a.loadCancel != nil {
+		a.loadCancel()
+	}
+	a.currentSong = nil
+	a.currentStation = nil
+	a.currentSongIndex = -1
+	a.isPlaying = false
+	a.loadingMux.Unlock()
+	if a.mpvInstance != nil && a.mpvInstance.Mpv != nil {
+		a.mpvInstance.Stop()
+	}
+	a.stopRadio()
+	a.clearQueue()
+
+	old := a.streamProxy
+	a.streamProxy = nil
+	if proxy, err := streamproxy.Start(client, openCache(name)); err != nil {
+		log.Println("start stream proxy failed:", err)
+	} else {
+		a.streamProxy = proxy
+	}
+	if old != nil {
+		old.Close()
+	}
+
+	a.subsonicClient = client
+	a.serverPrefix = prefix
+	if err := a.applyDefaultMusicFolder(); err != nil {
+		log.Println(err)
+	}
+	if err := a.loadMusic(); err != nil {
+		a.setMessage("[red]load music failed: " + err.Error())
+		return
+	}
+	a.setMessage("[lightgreen]connected to " + name)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rivo/tview"
+	"github.com/yhkl-dev/NaviCLI/subsonic"
+)
+
+// 服务器信息面板中展示的扩展及对应功能
+var extensionFeatures = []struct {
+	name    string
+	feature string
+}{
+	{subsonic.ExtSongLyrics, "synced lyrics"},
+	{subsonic.ExtTranscodeOffset, "server-side resume offset"},
+	{subsonic.ExtFormPost, "POST form requests"},
+	{subsonic.ExtAPIKeyAuth, "API key authentication"},
+}
+
+// showServerInfo 显示服务器类型、版本及 OpenSubsonic 扩展
+func (a *Application) showServerInfo() {
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true)
+	view.SetBorder(true).SetTitle(" Server Info ")
+	view.SetText(a.renderServerInfo())
+
+	a.showOverlay("server-info", view, 70, 24)
+}
+
+func (a *Application) renderServerInfo() string {
+	client := a.subsonicClient
+	info := client.ServerInfo()
+	if info == nil {
+		return "[yellow]Server capabilities have not been detected yet"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[gray]URL:          [-]%s\n", tview.Escape(client.BaseURL))
+	fmt.Fprintf(&b, "[gray]Server:       [-]%s %s\n", tview.Escape(info.Type), tview.Escape(info.ServerVersion))
+	fmt.Fprintf(&b, "[gray]API version:  [-]%s [darkgray](client %s)\n", info.APIVersion, subsonic.DefaultAPIVersion)
+	fmt.Fprintf(&b, "[gray]OpenSubsonic: [-]%t\n", info.OpenSubsonic)
+
+	auth := "token"
+	if client.APIKey != "" && client.Supports(subsonic.ExtAPIKeyAuth) {
+		auth = "API key"
+	}
+	fmt.Fprintf(&b, "[gray]Auth:         [-]%s\n", auth)
+
+	b.WriteString("\n[yellow]Features\n")
+	for _, ext := range extensionFeatures {
+		status := "[darkgray]unavailable"
+		if client.Supports(ext.name) {
+			status = "[lightgreen]enabled"
+		}
+		fmt.Fprintf(&b, "[gray]%-28s %s\n", ext.feature, status)
+	}
+
+	if len(info.Extensions) > 0 {
+		b.WriteString("\n[yellow]Extensions\n")
+		extensions := append([]subsonic.Extension(nil), info.Extensions...)
+		sort.Slice(extensions, func(i, j int) bool {
+			return extensions[i].Name < extensions[j].Name
+		})
+		for _, ext := range extensions {
+			fmt.Fprintf(&b, "[gray]%-28s [-]%v\n", tview.Escape(ext.Name), ext.Versions)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,361 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/wildeyedskies/go-mpv/mpv"
+)
+
+// sleepFade 睡眠定时器结束前音量渐弱的时长
+const sleepFade = 30 * time.Second
+
+// 睡眠定时器模式：到时间、当前歌曲结束、当前专辑结束
+const (
+	sleepAfterTime  = "time"
+	sleepAfterTrack = "track"
+	sleepAfterAlbum = "album"
+)
+
+// sleepPresets z 键依次切换的定时
+var sleepPresets = []string{"15", "30", "60", sleepAfterTrack, sleepAfterAlbum, "off"}
+
+type sleepTimer struct {
+	mode     string
+	deadline time.Time // 仅 time 模式
+	label    string    // z 键切换时的当前项
+	volume   float64   // 开始渐弱前的音量，0 表示尚未渐弱
+	cancel   context.CancelFunc
+}
+
+type alarm struct {
+	at       time.Time
+	playlist string
+	ringing  bool // 已开始播放，正在渐强音量
+	cancel   context.CancelFunc
+}
+
+// sleepCommand 设置睡眠定时器：分钟数、track、album 或 off，无参数时依次切换常用定时
+func (a *Application) sleepCommand(arg string) error {
+	if arg == "" {
+		a.loadingMux.Lock()
+		label := ""
+		if a.sleep != nil {
+			label = a.sleep.label
+		}
+		a.loadingMux.Unlock()
+
+		arg = sleepPresets[0]
+		for i, preset := range sleepPresets {
+			if preset == label {
+				arg = sleepPresets[(i+1)%len(sleepPresets)]
+			}
+		}
+	}
+
+	t := &sleepTimer{label: arg}
+	switch arg {
+	case "off":
+		a.stopSleepTimer()
+		a.setMessage("[lightgreen]sleep timer off")
+		return nil
+	case sleepAfterTrack, sleepAfterAlbum:
+		t.mode = arg
+	default:
+		minutes, err := strconv.ParseFloat(arg, 64)
+		if err != nil || minutes <= 0 {
+			return fmt.Errorf("invalid sleep timer: %q (minutes, track, album or off)", arg)
+		}
+		t.mode = sleepAfterTime
+		t.deadline = time.Now().Add(time.Duration(minutes * float64(time.Minute)))
+	}
+
+	a.stopSleepTimer()
+	ctx, cancel := context.WithCancel(a.ctx)
+	t.cancel = cancel
+	a.loadingMux.Lock()
+	a.sleep = t
+	a.loadingMux.Unlock()
+	go a.runSleepTimer(ctx, t)
+
+	a.setMessage("[lightgreen]" + a.sleepDisplay())
+	return nil
+}
+
+// runSleepTimer 每秒检查剩余时间，最后 30 秒逐渐降低音量，time 模式到时暂停播放。
+// track 和 album 模式由 sleepStopsPlayback 在歌曲结束时停止
+func (a *Application) runSleepTimer(ctx context.Context, t *sleepTimer) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		remaining, ok := a.sleepRemaining(t)
+		if !ok || remaining > sleepFade || a.mpvInstance == nil || a.mpvInstance.Mpv == nil {
+			continue
+		}
+
+		a.loadingMux.Lock()
+		volume := t.volume
+		a.loadingMux.Unlock()
+		if volume == 0 {
+			current, err := a.mpvInstance.GetProperty("volume", mpv.FORMAT_DOUBLE)
+			if err != nil {
+				continue
+			}
+			volume = current.(float64)
+			a.loadingMux.Lock()
+			t.volume = volume
+			a.loadingMux.Unlock()
+		}
+		a.mpvInstance.SetProperty("volume", mpv.FORMAT_DOUBLE, volume*max(remaining.Seconds(), 0)/sleepFade.Seconds())
+
+		if t.mode == sleepAfterTime && remaining <= 0 {
+			a.mpvInstance.SetProperty("pause", mpv.FORMAT_FLAG, true)
+			a.loadingMux.Lock()
+			a.isPlaying = false
+			a.loadingMux.Unlock()
+			a.stopSleepTimer()
+			a.setMessage("[lightgreen]sleep timer: playback stopped")
+			return
+		}
+	}
+}
+
+// sleepRemaining 距离定时器停止播放的时间，按播放速度折算
+func (a *Application) sleepRemaining(t *sleepTimer) (time.Duration, bool) {
+	if t.mode == sleepAfterTime {
+		return time.Until(t.deadline), true
+	}
+
+	a.loadingMux.Lock()
+	defer a.loadingMux.Unlock()
+	song := a.currentSong
+	if song == nil || a.currentStation != nil || !a.isPlaying || song.Duration <= 0 {
+		return 0, false
+	}
+
+	seconds := max(float64(song.Duration)-a.playPosition, 0)
+	// 专辑模式加上列表中紧随其后的同专辑歌曲
+	if t.mode == sleepAfterAlbum && song.AlbumID != "" {
+		for i := a.currentSongIndex + 1; i >= 1 && i < len(a.totalSongs) && a.totalSongs[i].AlbumID == song.AlbumID; i++ {
+			seconds += float64(a.totalSongs[i].Duration)
+		}
+	}
+	speed := a.speed
+	if speed <= 0 {
+		speed = 1
+	}
+	return time.Duration(seconds / speed * float64(time.Second)), true
+}
+
+// sleepStopsPlayback 歌曲播放结束时调用，track 模式或专辑播完时停止并返回 true
+func (a *Application) sleepStopsPlayback() bool {
+	a.loadingMux.Lock()
+	t := a.sleep
+	stop := false
+	if t != nil {
+		switch t.mode {
+		case sleepAfterTrack:
+			stop = true
+		case sleepAfterAlbum:
+			next := a.currentSongIndex + 1
+			stop = a.currentSong == nil || next < 1 || next >= len(a.totalSongs) ||
+				a.totalSongs[next].AlbumID != a.currentSong.AlbumID
+		}
+	}
+	if stop {
+		a.isPlaying = false
+	}
+	a.loadingMux.Unlock()
+
+	if stop {
+		a.stopSleepTimer()
+		a.setMessage("[lightgreen]sleep timer: playback stopped")
+	}
+	return stop
+}
+
+// stopSleepTimer 取消睡眠定时器并恢复渐弱前的音量
+func (a *Application) stopSleepTimer() {
+	a.loadingMux.Lock()
+	t := a.sleep
+	a.sleep = nil
+	a.loadingMux.Unlock()
+	if t == nil {
+		return
+	}
+	t.cancel()
+	a.loadingMux.Lock()
+	volume := t.volume
+	a.loadingMux.Unlock()
+	if volume > 0 && a.mpvInstance != nil && a.mpvInstance.Mpv != nil {
+		a.mpvInstance.SetProperty("volume", mpv.FORMAT_DOUBLE, volume)
+	}
+}
+
+// alarmCommand 设置闹钟：alarm 07:30 "Morning" 在该时间播放播放列表并渐强音量，alarm off 取消
+func (a *Application) alarmCommand(arg string) error {
+	if arg == "off" {
+		a.stopAlarm()
+		a.setMessage("[lightgreen]alarm off")
+		return nil
+	}
+
+	clock, playlist, _ := strings.Cut(arg, " ")
+	playlist = strings.Trim(strings.TrimSpace(playlist), `"`)
+	at, err := time.ParseInLocation("15:04", clock, time.Local)
+	if err != nil || playlist == "" {
+		return errors.New(`usage: alarm <HH:MM> "<playlist>" or alarm off`)
+	}
+	now := time.Now()
+	at = time.Date(now.Year(), now.Month(), now.Day(), at.Hour(), at.Minute(), 0, 0, time.Local)
+	if !at.After(now) {
+		at = at.AddDate(0, 0, 1)
+	}
+
+	a.stopAlarm()
+	ctx, cancel := context.WithCancel(a.ctx)
+	al := &alarm{at: at, playlist: playlist, cancel: cancel}
+	a.loadingMux.Lock()
+	a.alarm = al
+	a.loadingMux.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Until(at)):
+		}
+		// 渐强结束前闹钟保持可见，alarm off 可以取消渐强
+		a.loadingMux.Lock()
+		al.ringing = true
+		a.loadingMux.Unlock()
+		if err := a.wakeUp(ctx, playlist); err != nil {
+			a.setMessage("[red]alarm failed: " + err.Error())
+		}
+		a.loadingMux.Lock()
+		if a.alarm == al {
+			a.alarm = nil
+		}
+		a.loadingMux.Unlock()
+		cancel()
+	}()
+
+	a.setMessage(fmt.Sprintf("[lightgreen]alarm set for %s (%s)", at.Format("Mon 15:04"), playlist))
+	return nil
+}
+
+func (a *Application) stopAlarm() {
+	a.loadingMux.Lock()
+	if a.alarm != nil {
+		a.alarm.cancel()
+		a.alarm = nil
+	}
+	a.loadingMux.Unlock()
+}
+
+// wakeUp 加载播放列表，从静音开始播放并在 alarm.fade_seconds 内渐强到 alarm.volume。
+// 用户在渐强期间调整了音量时停止渐强，保留用户的音量
+func (a *Application) wakeUp(ctx context.Context, name string) error {
+	playlists, err := a.subsonicClient.ListPlaylistsContext(ctx)
+	if err != nil {
+		return err
+	}
+	id := ""
+	for _, playlist := range playlists {
+		if strings.EqualFold(playlist.Name, name) {
+			id = playlist.ID
+		}
+	}
+	if id == "" {
+		return fmt.Errorf("playlist not found: %s", name)
+	}
+	playlist, err := a.subsonicClient.GetPlaylistContext(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(playlist.Songs) == 0 {
+		return fmt.Errorf("playlist is empty: %s", name)
+	}
+	if a.mpvInstance == nil || a.mpvInstance.Mpv == nil {
+		return errors.New("player is not available")
+	}
+
+	target := viper.GetFloat64("alarm.volume")
+	fade := time.Duration(viper.GetInt("alarm.fade_seconds")) * time.Second
+	a.mpvInstance.SetProperty("mute", mpv.FORMAT_FLAG, false)
+	a.mpvInstance.SetProperty("volume", mpv.FORMAT_DOUBLE, 0.0)
+
+	a.stopRadio()
+	a.replaceSongs("playlist", playlist.Songs)
+	a.playSongAtIndex(0)
+	a.setMessage("[lightgreen]good morning: " + name)
+
+	if fade <= 0 {
+		a.mpvInstance.SetProperty("volume", mpv.FORMAT_DOUBLE, target)
+		return nil
+	}
+	started := time.Now()
+	last := 0.0
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		current, err := a.mpvInstance.GetProperty("volume", mpv.FORMAT_DOUBLE)
+		if err == nil && math.Abs(current.(float64)-last) > 0.5 {
+			return nil
+		}
+		progress := min(time.Since(started).Seconds()/fade.Seconds(), 1)
+		last = target * progress
+		a.mpvInstance.SetProperty("volume", mpv.FORMAT_DOUBLE, last)
+		if progress >= 1 {
+			return nil
+		}
+	}
+}
+
+// sleepDisplay 底部状态栏中的睡眠定时器和闹钟倒计时
+func (a *Application) sleepDisplay() string {
+	a.loadingMux.Lock()
+	t := a.sleep
+	al := a.alarm
+	ringing := al != nil && al.ringing
+	a.loadingMux.Unlock()
+
+	var parts []string
+	if t != nil {
+		label := "sleep"
+		if t.mode != sleepAfterTime {
+			label = "sleep after " + t.mode
+		}
+		if remaining, ok := a.sleepRemaining(t); ok {
+			label += " " + formatDuration(int(max(remaining, 0).Seconds()))
+		}
+		parts = append(parts, "[yellow]"+label)
+	}
+	if ringing {
+		parts = append(parts, "[yellow]alarm fading in")
+	} else if al != nil {
+		parts = append(parts, fmt.Sprintf("[yellow]alarm %s [darkgray](in %s)", al.at.Format("15:04"), formatDuration(int(time.Until(al.at).Seconds()))))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " " + strings.Join(parts, " ")
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/yhkl-dev/NaviCLI/subsonic"
+)
+
+// speedStep [ 和 ] 每次调整的速度
+const speedStep = 0.1
+
+// defaultSpeed 按内容类型读取配置 speed.music、speed.podcast、speed.audiobook
+func defaultSpeed(song subsonic.Song) float64 {
+	kind := song.Type
+	if kind != "podcast" && kind != "audiobook" {
+		kind = "music"
+	}
+	if speed := viper.GetFloat64("speed." + kind); speed > 0 {
+		return speed
+	}
+	return 1
+}
+
+// setSpeed 设置播放速度并记录实际生效的值
+func (a *Application) setSpeed(speed float64) {
+	if a.mpvInstance == nil || a.mpvInstance.Mpv == nil {
+		return
+	}
+	speed, err := a.mpvInstance.SetSpeed(speed, viper.GetBool("speed.keep_pitch"))
+	if err != nil {
+		log.Println("set speed failed:", err)
+	}
+	a.loadingMux.Lock()
+	a.speed = speed
+	a.loadingMux.Unlock()
+}
+
+func (a *Application) currentSpeed() float64 {
+	a.loadingMux.Lock()
+	defer a.loadingMux.Unlock()
+	if a.speed <= 0 {
+		return 1
+	}
+	return a.speed
+}
+
+// speedCommand 设置速度：1.5、+0.1、-0.1，无参数或 reset 恢复当前内容类型的默认速度
+func (a *Application) speedCommand(arg string) error {
+	a.loadingMux.Lock()
+	song := a.currentSong
+	station := a.currentStation
+	a.loadingMux.Unlock()
+	if song == nil {
+		return fmt.Errorf("nothing is playing")
+	}
+	if station != nil {
+		return fmt.Errorf("speed can't be changed for live radio")
+	}
+
+	arg = strings.TrimSuffix(arg, "x")
+	var speed float64
+	switch {
+	case arg == "" || arg == "reset":
+		speed = defaultSpeed(*song)
+	default:
+		value, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return fmt.Errorf("invalid speed: %q", arg)
+		}
+		speed = value
+		if strings.HasPrefix(arg, "+") || strings.HasPrefix(arg, "-") {
+			speed += a.currentSpeed()
+		}
+	}
+
+	a.setSpeed(speed)
+	a.setMessage(fmt.Sprintf("[lightgreen]speed %gx", a.currentSpeed()))
+	return nil
+}
+
+func (a *Application) stepSpeed(step float64) error {
+	return a.speedCommand(fmt.Sprintf("%+.2f", step))
+}
+
+// speedDisplay 进度条中的速度和按速度折算后的剩余时间，1x 时返回空字符串
+func speedDisplay(speed, position, duration float64) string {
+	if speed == 1 {
+		return ""
+	}
+	text := fmt.Sprintf(" [yellow]%gx", speed)
+	if duration > 0 {
+		text += fmt.Sprintf("[darkgray] -%s", formatDuration(int(max(duration-position, 0)/speed)))
+	}
+	return text
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/yhkl-dev/NaviCLI/mpvplayer"
+	"github.com/yhkl-dev/NaviCLI/subsonic"
+)
+
+func (a *Application) isPlayingStation() bool {
+	a.loadingMux.Lock()
+	defer a.loadingMux.Unlock()
+	return a.currentStation != nil
+}
+
+// playStation 播放网络电台，电台地址不含认证信息，直接交给 mpv
+func (a *Application) playStation(station subsonic.InternetRadioStation) {
+	a.recordPlay(playStopped)
+	a.stopRadio()
+
+	a.loadingMux.Lock()
+	if a.isLoading {
+		a.loadingMux.Unlock()
+		return
+	}
+	a.currentStation = &station
+	a.currentSong = &subsonic.Song{
+		ID:     station.ID,
+		Title:  station.Name,
+		Artist: "Internet Radio",
+	}
+	a.currentSongIndex = -1
+	a.isPlaying = false
+	a.playOffset = 0
+	a.loadingMux.Unlock()
+
+	if a.mpvInstance == nil || a.mpvInstance.Mpv == nil {
+		return
+	}
+
+	a.mpvInstance.Queue = []mpvplayer.QueueItem{{
+		Id:    station.ID,
+		Uri:   station.StreamURL,
+		Title: station.Name,
+	}}
+	a.mpvInstance.Stop()
+	time.Sleep(50 * time.Millisecond)
+	a.setSpeed(1)
+	a.setLoop(-1, -1)
+	a.mpvInstance.Play(station.StreamURL)
+
+	a.loadingMux.Lock()
+	a.isPlaying = true
+	a.loadingMux.Unlock()
+
+	a.application.QueueUpdateDraw(func() {
+		a.statusBar.SetText(stationInfo(&station, "", "[yellow]Connecting..."))
+	})
+}
+
+func stationInfo(station *subsonic.InternetRadioStation, streamTitle, progressBar string) string {
+	if streamTitle == "" {
+		streamTitle = "-"
+	}
+	return fmt.Sprintf(`
+[-]Radio:
+[lightgreen]%s
+
+[darkgray][stream] [-]%s
+[darkgray][source] %s
+[darkgray][favourite]
+
+[gray]%s
+%s`,
+		tview.Escape(station.Name),
+		tview.Escape(streamTitle),
+		tview.Escape(station.HomePageURL),
+		tview.Escape(station.StreamURL),
+		progressBar)
+}
+
+// showStations 打开网络电台列表：Enter 播放，a 添加，e 编辑，d 删除
+func (a *Application) showStations() {
+	go func() {
+		stations, err := a.subsonicClient.GetInternetRadioStationsContext(a.ctx)
+		if err != nil {
+			a.application.QueueUpdateDraw(func() {
+				a.statusBar.SetText("[red]load radio stations failed: " + err.Error())
+			})
+			return
+		}
+		a.application.QueueUpdateDraw(func() {
+			a.stationsUI(stations)
+		})
+	}()
+}
+
+func (a *Application) stationsUI(stations []subsonic.InternetRadioStation) {
+	list := tview.NewList()
+	list.SetBorder(true).SetTitle(" Internet Radio (Enter play, a add, e edit, d delete) ")
+	for _, station := range stations {
+		list.AddItem(tview.Escape(station.Name), "[darkgray]"+tview.Escape(station.StreamURL), 0, nil)
+	}
+	if len(stations) == 0 {
+		list.AddItem("[darkgray]No stations, press a to add one", "", 0, nil)
+	}
+
+	list.SetSelectedFunc(func(index int, _, _ string, _ rune) {
+		if index >= len(stations) {
+			return
+		}
+		a.closeOverlay()
+		go a.playStation(stations[index])
+	})
+
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		index := list.GetCurrentItem()
+		switch event.Rune() {
+		case 'a':
+			a.stationForm(nil)
+			return nil
+		case 'e':
+			if index < len(stations) {
+				a.stationForm(&stations[index])
+			}
+			return nil
+		case 'd':
+			if index < len(stations) {
+				a.deleteStation(stations[index])
+			}
+			return nil
+		}
+		return event
+	})
+
+	a.showOverlay("stations", list, 80, 20)
+}
+
+// stationForm 添加或编辑网络电台，station 为 nil 时为添加
+func (a *Application) stationForm(station *subsonic.InternetRadioStation) {
+	title := " Add Station "
+	current := subsonic.InternetRadioStation{}
+	if station != nil {
+		title = " Edit Station "
+		current = *station
+	}
+
+	form := tview.NewForm().
+		AddInputField("Name", current.Name, 40, nil, nil).
+		AddInputField("Stream URL", current.StreamURL, 40, nil, nil).
+		AddInputField("Homepage", current.HomePageURL, 40, nil, nil)
+	form.SetBorder(true).SetTitle(title)
+
+	text := func(label string) string {
+		return form.GetFormItemByLabel(label).(*tview.InputField).GetText()
+	}
+
+	form.AddButton("Save", func() {
+		name, streamURL, homePage := text("Name"), text("Stream URL"), text("Homepage")
+		if name == "" || streamURL == "" {
+			return
+		}
+		go func() {
+			var err error
+			if station == nil {
+				err = a.subsonicClient.CreateInternetRadioStationContext(a.ctx, streamURL, name, homePage)
+			} else {
+				err = a.subsonicClient.UpdateInternetRadioStationContext(a.ctx, station.ID, streamURL, name, homePage)
+			}
+			if err != nil {
+				a.application.QueueUpdateDraw(func() {
+					a.closeOverlay()
+					a.statusBar.SetText("[red]save radio station failed: " + err.Error())
+				})
+				return
+			}
+			a.showStations()
+		}()
+	})
+	form.AddButton("Cancel", func() {
+		a.showStations()
+	})
+
+	a.showOverlay("station-form", form, 60, 11)
+}
+
+func (a *Application) deleteStation(station subsonic.InternetRadioStation) {
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Delete radio station %q?", station.Name)).
+		AddButtons([]string{"Delete", "Cancel"}).
+		SetDoneFunc(func(_ int, label string) {
+			if label != "Delete" {
+				a.showStations()
+				return
+			}
+			go func() {
+				if err := a.subsonicClient.DeleteInternetRadioStationContext(a.ctx, station.ID); err != nil {
+					a.application.QueueUpdateDraw(func() {
+						a.closeOverlay()
+						a.statusBar.SetText("[red]delete radio station failed: " + err.Error())
+					})
+					return
+				}
+				a.showStations()
+			}()
+		})
+
+	a.showOverlay("station-delete", modal, 50, 7)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/yhkl-dev/NaviCLI/history"
+)
+
+// showStats 显示本地收听统计，w/m/y 切换统计周期
+func (a *Application) showStats() {
+	if a.history == nil {
+		a.statusBar.SetText("[red]listening history is not available")
+		return
+	}
+
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(false)
+	view.SetBorder(true).SetTitle(" Listening Stats ")
+
+	period := history.PeriodWeek
+	render := func() {
+		stats := a.history.Stats(period, time.Now(), 10)
+		view.SetText(renderStats(stats))
+		view.ScrollToBeginning()
+	}
+
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'w':
+			period = history.PeriodWeek
+		case 'm':
+			period = history.PeriodMonth
+		case 'y':
+			period = history.PeriodYear
+		default:
+			return event
+		}
+		render()
+		return nil
+	})
+
+	render()
+	a.showOverlay("stats", view, 80, 30)
+}
+
+func renderStats(stats history.Stats) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "[-]Period: [lightgreen]%s [darkgray](w/m/y to switch, ESC to close)\n\n", stats.Period)
+	fmt.Fprintf(&b, "[gray]Plays:          [-]%d\n", stats.Plays)
+	fmt.Fprintf(&b, "[gray]Listening time: [-]%s\n", formatListening(int(stats.Listened.Seconds())))
+	fmt.Fprintf(&b, "[gray]Skip rate:      [-]%.1f%%\n", stats.SkipRate()*100)
+
+	sections := []struct {
+		title  string
+		counts []history.Count
+	}{
+		{"Top Artists", stats.TopArtists},
+		{"Top Albums", stats.TopAlbums},
+		{"Top Tracks", stats.TopTracks},
+	}
+	for _, section := range sections {
+		fmt.Fprintf(&b, "\n[yellow]%s\n", section.title)
+		if len(section.counts) == 0 {
+			b.WriteString("[darkgray]  no plays yet\n")
+			continue
+		}
+		for i, c := range section.counts {
+			fmt.Fprintf(&b, "[lightgreen]%2d: [-]%s [darkgray](%d plays, %s)\n",
+				i+1, tview.Escape(c.Name), c.Plays, formatListening(c.Listened))
+		}
+	}
+	return b.String()
+}
+
+// formatListening 收听总时长，按小时和分钟显示，不足一小时时显示分钟和秒
+func formatListening(seconds int) string {
+	hours, minutes := seconds/3600, seconds%3600/60
+	if hours > 0 {
+		return fmt.Sprintf("%dh %02dm", hours, minutes)
+	}
+	return fmt.Sprintf("%dm %02ds", minutes, seconds%60)
+}
@@ -0,0 +1,21 @@
+package main
+
+import "testing"
+
+func TestFormatListening(t *testing.T) {
+	tests := []struct {
+		seconds int
+		want    string
+	}{
+		{0, "0m 00s"},
+		{59, "0m 59s"},
+		{754, "12m 34s"},
+		{3600, "1h 00m"},
+		{4445296, "1234h 48m"},
+	}
+	for _, tt := range tests {
+		if got := formatListening(tt.seconds); got != tt.want {
+			t.Errorf("formatListening(%d) = %q, want %q", tt.seconds, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package streamproxy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Cache 离线缓存目录，保存完整下载过的歌曲
+type Cache struct {
+	dir     string
+	maxSize int64 // 字节，0 表示不限制
+}
+
+func NewCache(dir string, maxSize int64) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create cache dir failed: %w", err)
+	}
+	// 清理上次异常退出留下的临时文件
+	if parts, err := filepath.Glob(filepath.Join(dir, "*.part")); err == nil {
+		for _, part := range parts {
+			os.Remove(part)
+		}
+	}
+	return &Cache{dir: dir, maxSize: maxSize}, nil
+}
+
+// fileName 缓存文件名取歌曲 ID 的哈希，ID 为 "."、".." 或过长时也不会指向缓存目录之外
+func fileName(songID string) string {
+	sum := sha256.Sum256([]byte(songID))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *Cache) path(songID string) string {
+	return filepath.Join(c.dir, fileName(songID))
+}
+
+// Has 判断歌曲是否已缓存
+func (c *Cache) Has(songID string) bool {
+	_, err := os.Stat(c.path(songID))
+	return err == nil
+}
+
+func (c *Cache) Open(songID string) (*os.File, error) {
+	return os.Open(c.path(songID))
+}
+
+// tempFile 创建下载中的临时文件，完成后通过 commit 移入缓存
+func (c *Cache) tempFile(songID string) (*os.File, error) {
+	return os.CreateTemp(c.dir, fileName(songID)+".*.part")
+}
+
+func (c *Cache) commit(songID, tempPath string) error {
+	if err := os.Rename(tempPath, c.path(songID)); err != nil {
+		return err
+	}
+	c.evict()
+	return nil
+}
+
+// evict 超过容量上限时按修改时间删除最旧的缓存
+func (c *Cache) evict() {
+	if c.maxSize <= 0 {
+		return
+	}
+
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+
+	type cached struct {
+		path string
+		info os.FileInfo
+	}
+	files := make([]cached, 0, len(entries))
+	var total int64
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) == ".part" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, cached{filepath.Join(c.dir, entry.Name()), info})
+		total += info.Size()
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].info.ModTime().Before(files[j].info.ModTime())
+	})
+	for _, f := range files {
+		if total <= c.maxSize {
+			return
+		}
+		if os.Remove(f.path) == nil {
+			total -= f.info.Size()
+		}
+	}
+}
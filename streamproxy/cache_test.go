@@ -0,0 +1,52 @@
+package streamproxy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCachePathStaysInDir(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "songs")
+	c, err := NewCache(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 缓存目录之外的文件不能被当作已缓存的歌曲
+	if err := os.WriteFile(filepath.Join(root, "outside"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"", ".", "..", "../outside", "a/b", "..%2Foutside"} {
+		if got := filepath.Dir(c.path(id)); got != dir {
+			t.Errorf("path(%q) = %q, outside the cache dir", id, c.path(id))
+		}
+		if c.Has(id) {
+			t.Errorf("Has(%q) = true for an empty cache", id)
+		}
+	}
+	if c.path("a") == c.path("b") {
+		t.Error("different songs share a cache file")
+	}
+}
+
+func TestCacheCommit(t *testing.T) {
+	c, err := NewCache(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := c.tempFile("..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("data")
+	f.Close()
+
+	if err := c.commit("..", f.Name()); err != nil {
+		t.Fatal(err)
+	}
+	if !c.Has("..") {
+		t.Error("committed song is not cached")
+	}
+}
@@ -0,0 +1,271 @@
+package streamproxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	maxFetchRetries = 5
+	fetchBackoff    = 500 * time.Millisecond
+	chunkSize       = 32 * 1024
+)
+
+// download 一首歌曲的后台预读：以最快速度把音频写入临时文件，
+// mpv 的请求从临时文件读取，断线时带 Range 续传，完成后存入缓存
+type download struct {
+	songID string
+	file   *os.File
+	cancel context.CancelFunc
+
+	// 以下两项由 Server.mu 保护
+	readers    int  // 正在读取的 mpv 请求数
+	superseded bool // 已切到其他歌曲，最后一个读取者结束后取消
+
+	mu          sync.Mutex
+	cond        *sync.Cond
+	written     int64
+	total       int64 // -1 表示服务器未返回长度（转码）
+	contentType string
+	ready       bool // 已收到响应头
+	done        bool
+	cached      bool // 已移入离线缓存
+	err         error
+}
+
+func newDownload(songID string, file *os.File, cancel context.CancelFunc) *download {
+	d := &download{
+		songID: songID,
+		file:   file,
+		cancel: cancel,
+		total:  -1,
+	}
+	d.cond = sync.NewCond(&d.mu)
+	return d
+}
+
+// waitReady 等待响应头或下载失败
+func (d *download) waitReady(ctx context.Context) error {
+	stop := context.AfterFunc(ctx, d.broadcast)
+	defer stop()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for !d.ready && d.err == nil && ctx.Err() == nil {
+		d.cond.Wait()
+	}
+	if d.err != nil {
+		return d.err
+	}
+	return ctx.Err()
+}
+
+// readAt 从 pos 读取数据，数据未下载到时阻塞等待，下载完成且读到末尾时返回 io.EOF
+func (d *download) readAt(ctx context.Context, buf []byte, pos int64) (int, error) {
+	stop := context.AfterFunc(ctx, d.broadcast)
+	defer stop()
+
+	d.mu.Lock()
+	for d.written <= pos && !d.done && d.err == nil && ctx.Err() == nil {
+		d.cond.Wait()
+	}
+	written, done, err := d.written, d.done, d.err
+	d.mu.Unlock()
+
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+	if pos >= written {
+		if err != nil {
+			return 0, err
+		}
+		if done {
+			return 0, io.EOF
+		}
+	}
+
+	n := int(min(int64(len(buf)), written-pos))
+	return d.file.ReadAt(buf[:n], pos)
+}
+
+func (d *download) broadcast() {
+	d.mu.Lock()
+	d.cond.Broadcast()
+	d.mu.Unlock()
+}
+
+func (d *download) finish(err error) {
+	d.mu.Lock()
+	d.done = err == nil
+	d.err = err
+	d.cond.Broadcast()
+	d.mu.Unlock()
+}
+
+// run 下载完整音频，连接中断时自动重试
+func (d *download) run(ctx context.Context, client *http.Client, streamURL func() string) error {
+	var lastErr error
+	for attempt := 0; attempt <= maxFetchRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(fetchBackoff << (attempt - 1)):
+			}
+		}
+
+		err := d.fetch(ctx, client, streamURL())
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		var statusErr *upstreamStatusError
+		if errors.As(err, &statusErr) && statusErr.code < 500 {
+			return err
+		}
+		lastErr = err
+	}
+	return fmt.Errorf("stream failed after %d retries: %w", maxFetchRetries, lastErr)
+}
+
+type upstreamStatusError struct {
+	code int
+}
+
+func (e *upstreamStatusError) Error() string {
+	return fmt.Sprintf("unexpected upstream status: %d", e.code)
+}
+
+// fetch 从已下载的位置继续请求，服务器不支持 Range 时丢弃已有部分
+func (d *download) fetch(ctx context.Context, client *http.Client, streamURL string) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", streamURL, nil)
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	written := d.written
+	d.mu.Unlock()
+	if written > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", written))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		if written > 0 {
+			if _, err := io.CopyN(io.Discard, resp.Body, written); err != nil {
+				return err
+			}
+		}
+	case http.StatusPartialContent:
+	default:
+		return &upstreamStatusError{code: resp.StatusCode}
+	}
+
+	d.mu.Lock()
+	if !d.ready {
+		d.ready = true
+		d.contentType = resp.Header.Get("Content-Type")
+		d.total = responseLength(resp)
+		d.cond.Broadcast()
+	}
+	d.mu.Unlock()
+
+	buf := make([]byte, chunkSize)
+	for {
+		n, readErr := resp.Body.Read(buf)
+		if n > 0 {
+			if _, err := d.file.Write(buf[:n]); err != nil {
+				return err
+			}
+			d.mu.Lock()
+			d.written += int64(n)
+			d.cond.Broadcast()
+			d.mu.Unlock()
+		}
+		if readErr == io.EOF {
+			return d.checkComplete()
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+}
+
+// checkComplete 已知长度时确认数据完整，不完整视为连接中断
+func (d *download) checkComplete() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.total >= 0 && d.written < d.total {
+		return io.ErrUnexpectedEOF
+	}
+	if d.total < 0 {
+		d.total = d.written
+	}
+	return nil
+}
+
+// responseLength 返回完整音频长度，未知时返回 -1
+func responseLength(resp *http.Response) int64 {
+	if resp.StatusCode == http.StatusPartialContent {
+		// Content-Range: bytes 100-199/200
+		contentRange := resp.Header.Get("Content-Range")
+		if i := strings.LastIndex(contentRange, "/"); i >= 0 {
+			if total, err := strconv.ParseInt(contentRange[i+1:], 10, 64); err == nil {
+				return total
+			}
+		}
+		return -1
+	}
+	return resp.ContentLength
+}
+
+func (d *download) health() BufferHealth {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return BufferHealth{
+		SongID:     d.songID,
+		Downloaded: d.written,
+		Total:      d.total,
+		Done:       d.done,
+		Cached:     d.cached,
+		Err:        d.err,
+	}
+}
+
+// BufferHealth 预读缓冲状态，供界面显示真实的下载进度
+type BufferHealth struct {
+	SongID     string
+	Downloaded int64
+	Total      int64 // -1 表示未知
+	Done       bool
+	Cached     bool
+	Err        error
+}
+
+// Progress 下载进度（0-1），长度未知时返回 -1
+func (h BufferHealth) Progress() float64 {
+	if h.Done || h.Cached {
+		return 1
+	}
+	if h.Total <= 0 {
+		return -1
+	}
+	return float64(h.Downloaded) / float64(h.Total)
+}
@@ -0,0 +1,392 @@
+package streamproxy
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yhkl-dev/NaviCLI/subsonic"
+)
+
+// 透传给服务器的请求头和返回给 mpv 的响应头
+var (
+	requestHeaders  = []string{"Range", "If-Range"}
+	responseHeaders = []string{"Content-Type", "Content-Length", "Content-Range", "Accept-Ranges", "Last-Modified", "ETag"}
+)
+
+// Server 本地回环流媒体代理。mpv 只访问 127.0.0.1 上不含认证信息的地址，
+// 由代理带上认证参数向服务器请求 stream.view，预读到本地并写入离线缓存
+type Server struct {
+	client   *subsonic.Client
+	upstream *http.Client
+	cache    *Cache
+	listener net.Listener
+	server   *http.Server
+	token    string // 路径前缀，防止本机其他进程通过代理访问音乐库
+
+	mu        sync.Mutex
+	downloads map[string]*download
+}
+
+// Start 启动代理，cache 为 nil 时不保留下载完成的歌曲
+func Start(client *subsonic.Client, cache *Cache) (*Server, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("listen stream proxy failed: %w", err)
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	s := &Server{
+		client: client,
+		// 音频流可能持续很久，不能使用带总超时的 API 客户端
+		upstream:  &http.Client{},
+		cache:     cache,
+		listener:  listener,
+		token:     hex.EncodeToString(b),
+		downloads: make(map[string]*download),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/"+s.token+"/stream/", s.handleStream)
+	s.server = &http.Server{Handler: mux}
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Println("stream proxy stopped:", err)
+		}
+	}()
+	return s, nil
+}
+
+// URL 返回 mpv 使用的播放地址，offset 的含义与 subsonic.Client.GetPlayURLAt 相同
+func (s *Server) URL(songID string, offset int) (string, bool) {
+	serverSeek := offset > 0 && s.client.Supports(subsonic.ExtTranscodeOffset)
+	playURL := fmt.Sprintf("http://%s/%s/stream/%s", s.listener.Addr(), s.token, url.PathEscape(songID))
+	if serverSeek {
+		playURL += "?offset=" + strconv.Itoa(offset)
+	}
+	return playURL, serverSeek
+}
+
+// Health 返回歌曲的预读状态
+func (s *Server) Health(songID string) (BufferHealth, bool) {
+	s.mu.Lock()
+	d, ok := s.downloads[songID]
+	s.mu.Unlock()
+
+	if ok {
+		return d.health(), true
+	}
+	if s.cache != nil && s.cache.Has(songID) {
+		return BufferHealth{SongID: songID, Done: true, Cached: true}, true
+	}
+	return BufferHealth{}, false
+}
+
+func (s *Server) Close() error {
+	s.mu.Lock()
+	for songID, d := range s.downloads {
+		s.drop(songID, d)
+	}
+	s.mu.Unlock()
+	return s.server.Close()
+}
+
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	songID := strings.TrimPrefix(r.URL.Path, "/"+s.token+"/stream/")
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	// 服务器端定位的转码流每次内容不同，不预读也不缓存
+	if offset > 0 {
+		s.passthrough(w, r, songID, offset)
+		return
+	}
+
+	if s.cache != nil && s.cache.Has(songID) {
+		if s.serveCached(w, r, songID) {
+			return
+		}
+	}
+
+	d, err := s.startDownload(songID)
+	if err != nil {
+		log.Println("stream proxy start download failed:", err)
+		http.Error(w, "start download failed", http.StatusInternalServerError)
+		return
+	}
+	defer s.release(songID, d)
+	if err := d.waitReady(r.Context()); err != nil {
+		http.Error(w, "upstream request failed", http.StatusBadGateway)
+		return
+	}
+	s.serveDownload(w, r, d)
+}
+
+func (s *Server) serveCached(w http.ResponseWriter, r *http.Request, songID string) bool {
+	f, err := s.cache.Open(songID)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	// 更新修改时间，淘汰缓存时保留最近播放的歌曲
+	now := time.Now()
+	os.Chtimes(f.Name(), now, now)
+
+	http.ServeContent(w, r, "", info.ModTime(), f)
+	return true
+}
+
+// startDownload 返回歌曲的预读任务并登记一个读取者，调用方结束请求时需调用 release。
+// 切歌时其他下载被标记为过期，没有读取者的立即取消，仍在被读取的
+// （如淡入淡出时另一个 mpv 正在播放的上一首）等最后一个请求结束后再取消
+func (s *Server) startDownload(songID string) (*download, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if d, ok := s.downloads[songID]; ok && d.health().Err == nil {
+		d.readers++
+		d.superseded = false
+		return d, nil
+	}
+	for id, other := range s.downloads {
+		other.superseded = true
+		if id == songID || other.readers == 0 {
+			s.drop(id, other)
+		}
+	}
+
+	var file *os.File
+	var err error
+	if s.cache != nil {
+		file, err = s.cache.tempFile(songID)
+	} else {
+		file, err = os.CreateTemp("", "navicli-*.part")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d := newDownload(songID, file, cancel)
+	d.readers = 1
+	s.downloads[songID] = d
+
+	go func() {
+		err := d.run(ctx, s.upstream, func() string {
+			streamURL, _ := s.client.GetPlayURLAt(songID, 0)
+			return streamURL
+		})
+		if err != nil && ctx.Err() == nil {
+			log.Println("stream proxy download failed:", subsonic.RedactError(err))
+		}
+		d.finish(err)
+
+		if err == nil && s.cache != nil {
+			if err := s.cache.commit(songID, file.Name()); err != nil {
+				log.Println("stream proxy cache failed:", err)
+				return
+			}
+			d.mu.Lock()
+			d.cached = true
+			d.mu.Unlock()
+		}
+	}()
+	return d, nil
+}
+
+// Save 将歌曲完整下载到离线缓存，与正在播放的预读互不影响
+func (s *Server) Save(ctx context.Context, songID string) error {
+	if s.cache == nil {
+		return errors.New("offline cache is disabled")
+	}
+	if s.cache.Has(songID) {
+		return nil
+	}
+
+	file, err := s.cache.tempFile(songID)
+	if err != nil {
+		return err
+	}
+	d := newDownload(songID, file, func() {})
+	err = d.run(ctx, s.upstream, func() string {
+		streamURL, _ := s.client.GetPlayURLAt(songID, 0)
+		return streamURL
+	})
+	file.Close()
+	if err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	return s.cache.commit(songID, file.Name())
+}
+
+// release 结束一个读取者，过期的下载在最后一个读取者结束时取消
+func (s *Server) release(songID string, d *download) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d.readers--
+	if d.readers == 0 && d.superseded && s.downloads[songID] == d {
+		s.drop(songID, d)
+	}
+}
+
+// drop 取消下载并删除未存入缓存的临时文件，调用方需持有 s.mu
+func (s *Server) drop(songID string, d *download) {
+	d.cancel()
+	d.file.Close()
+	if !d.health().Cached {
+		os.Remove(d.file.Name())
+	}
+	delete(s.downloads, songID)
+}
+
+// serveDownload 从预读文件响应 mpv 的请求，长度已知时支持 Range。
+// 请求的位置尚未下载到时（如不支持 transcodeOffset 时的断点续播）直接转发给服务器，
+// 不必等待预读追上
+func (s *Server) serveDownload(w http.ResponseWriter, r *http.Request, d *download) {
+	d.mu.Lock()
+	total, contentType, written, done := d.total, d.contentType, d.written, d.done
+	d.mu.Unlock()
+
+	start, end := int64(0), total
+	status := http.StatusOK
+	if rangeHeader := r.Header.Get("Range"); rangeHeader != "" && total > 0 {
+		first, last, ok := parseRange(rangeHeader, total)
+		if !ok {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", total))
+			http.Error(w, "invalid range", http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		if first > written && !done {
+			s.passthrough(w, r, d.songID, 0)
+			return
+		}
+		start, end = first, last+1
+		status = http.StatusPartialContent
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", first, last, total))
+	}
+
+	if contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+	if total >= 0 {
+		w.Header().Set("Accept-Ranges", "bytes")
+		w.Header().Set("Content-Length", strconv.FormatInt(end-start, 10))
+	}
+	w.WriteHeader(status)
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	flusher, _ := w.(http.Flusher)
+	buf := make([]byte, chunkSize)
+	for pos := start; end < 0 || pos < end; {
+		size := int64(len(buf))
+		if end >= 0 {
+			size = min(size, end-pos)
+		}
+		n, err := d.readAt(r.Context(), buf[:size], pos)
+		if n > 0 {
+			if _, werr := w.Write(buf[:n]); werr != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+			pos += int64(n)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// parseRange 解析单个 Range，返回包含两端的字节区间
+func parseRange(header string, total int64) (int64, int64, bool) {
+	spec, ok := strings.CutPrefix(header, "bytes=")
+	if !ok || strings.Contains(spec, ",") {
+		return 0, 0, false
+	}
+	first, last, ok := strings.Cut(spec, "-")
+	if !ok {
+		return 0, 0, false
+	}
+
+	// bytes=-500 表示最后 500 字节
+	if first == "" {
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n <= 0 {
+			return 0, 0, false
+		}
+		return max(total-n, 0), total - 1, true
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start >= total {
+		return 0, 0, false
+	}
+	end := total - 1
+	if last != "" {
+		end, err = strconv.ParseInt(last, 10, 64)
+		if err != nil || end < start {
+			return 0, 0, false
+		}
+		end = min(end, total-1)
+	}
+	return start, end, true
+}
+
+// passthrough 直接转发请求，不预读
+func (s *Server) passthrough(w http.ResponseWriter, r *http.Request, songID string, offset int) {
+	upstreamURL, _ := s.client.GetPlayURLAt(songID, offset)
+
+	req, err := http.NewRequestWithContext(r.Context(), r.Method, upstreamURL, nil)
+	if err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	for _, header := range requestHeaders {
+		if value := r.Header.Get(header); value != "" {
+			req.Header.Set(header, value)
+		}
+	}
+
+	resp, err := s.upstream.Do(req)
+	if err != nil {
+		log.Println("stream proxy request failed:", subsonic.RedactError(err))
+		http.Error(w, "upstream request failed", http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	for _, header := range responseHeaders {
+		if value := resp.Header.Get(header); value != "" {
+			w.Header().Set(header, value)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	io.Copy(w, resp.Body)
+}
@@ -0,0 +1,151 @@
+package streamproxy
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/yhkl-dev/NaviCLI/subsonic"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		header      string
+		total       int64
+		first, last int64
+		ok          bool
+	}{
+		{"bytes=0-", 1000, 0, 999, true},
+		{"bytes=0-99", 1000, 0, 99, true},
+		{"bytes=100-", 1000, 100, 999, true},
+		{"bytes=900-2000", 1000, 900, 999, true},
+		{"bytes=-500", 1000, 500, 999, true},
+		{"bytes=-5000", 1000, 0, 999, true},
+		{"bytes=-0", 1000, 0, 0, false},
+		{"bytes=1000-", 1000, 0, 0, false},
+		{"bytes=50-10", 1000, 0, 0, false},
+		{"bytes=0-10,20-30", 1000, 0, 0, false},
+		{"bytes=abc-", 1000, 0, 0, false},
+		{"bytes=10", 1000, 0, 0, false},
+		{"items=0-10", 1000, 0, 0, false},
+		{"", 1000, 0, 0, false},
+	}
+	for _, tt := range tests {
+		first, last, ok := parseRange(tt.header, tt.total)
+		if ok != tt.ok || (ok && (first != tt.first || last != tt.last)) {
+			t.Errorf("parseRange(%q, %d) = %d, %d, %v; want %d, %d, %v",
+				tt.header, tt.total, first, last, ok, tt.first, tt.last, tt.ok)
+		}
+	}
+}
+
+// testProxy 启动一个返回固定内容的上游服务器，gate 关闭前上游只返回前 half 字节
+func testProxy(t *testing.T, data []byte, half int, gate chan struct{}) *Server {
+	t.Helper()
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Range") != "" {
+			http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(data))
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.Write(data[:half])
+		w.(http.Flusher).Flush()
+		select {
+		case <-gate:
+		case <-r.Context().Done():
+			return
+		}
+		w.Write(data[half:])
+	}))
+	t.Cleanup(upstream.Close)
+
+	s, err := Start(subsonic.Init(upstream.URL, "user", "pass", "test", "1.16.1"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestSupersededDownloadKeptWhileRead(t *testing.T) {
+	gate := make(chan struct{})
+	defer close(gate)
+	s := testProxy(t, bytes.Repeat([]byte("a"), 1000), 100, gate)
+
+	old, err := s.startDownload("old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, err := s.startDownload("next")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.mu.Lock()
+	_, kept := s.downloads["old"]
+	s.mu.Unlock()
+	if !kept {
+		t.Fatal("download dropped while still being read")
+	}
+
+	s.release("old", old)
+	s.mu.Lock()
+	_, kept = s.downloads["old"]
+	s.mu.Unlock()
+	if kept {
+		t.Error("superseded download not dropped after its last reader")
+	}
+
+	s.release("next", next)
+	s.mu.Lock()
+	_, kept = s.downloads["next"]
+	s.mu.Unlock()
+	if !kept {
+		t.Error("current download dropped when its reader ended")
+	}
+}
+
+func TestRangeBeyondDownloadPassesThrough(t *testing.T) {
+	data := make([]byte, 1000)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	gate := make(chan struct{})
+	defer close(gate)
+	s := testProxy(t, data, 100, gate)
+
+	streamURL, _ := s.URL("song", 0)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// 第一个请求开始预读，上游在 100 字节处暂停
+	first, _ := http.NewRequestWithContext(ctx, "GET", streamURL, nil)
+	resp, err := http.DefaultClient.Do(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if _, err := io.ReadFull(resp.Body, make([]byte, 100)); err != nil {
+		t.Fatal(err)
+	}
+
+	req, _ := http.NewRequestWithContext(ctx, "GET", streamURL, nil)
+	req.Header.Set("Range", "bytes=800-")
+	resp2, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp2.Body.Close()
+	body, err := io.ReadAll(resp2.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp2.StatusCode != http.StatusPartialContent || !bytes.Equal(body, data[800:]) {
+		t.Errorf("status %d, got %d bytes, want bytes 800-999", resp2.StatusCode, len(body))
+	}
+}
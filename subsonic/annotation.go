@@ -0,0 +1,45 @@
+package subsonic
+
+import (
+	"context"
+	"net/url"
+)
+
+// Star 收藏歌曲
+func (c *Client) Star(songIDs []string) error {
+	return c.StarContext(context.Background(), songIDs)
+}
+
+func (c *Client) StarContext(ctx context.Context, songIDs []string) error {
+	_, err := c.requestValues(ctx, "star", url.Values{"id": songIDs})
+	return err
+}
+
+func (c *Client) Unstar(songIDs []string) error {
+	return c.UnstarContext(context.Background(), songIDs)
+}
+
+func (c *Client) UnstarContext(ctx context.Context, songIDs []string) error {
+	_, err := c.requestValues(ctx, "unstar", url.Values{"id": songIDs})
+	return err
+}
+
+// CreateShare 为歌曲创建公开分享链接
+func (c *Client) CreateShare(songIDs []string, description string) (Share, error) {
+	return c.CreateShareContext(context.Background(), songIDs, description)
+}
+
+func (c *Client) CreateShareContext(ctx context.Context, songIDs []string, description string) (Share, error) {
+	params := url.Values{"id": songIDs}
+	if description != "" {
+		params.Set("description", description)
+	}
+	resp, err := c.requestValues(ctx, "createShare", params)
+	if err != nil {
+		return Share{}, err
+	}
+	if len(resp.Response.Shares.Shares) == 0 {
+		return Share{}, &Error{Code: ErrorGeneric, Message: "createShare returned no share"}
+	}
+	return resp.Response.Shares.Shares[0], nil
+}
@@ -21,12 +21,14 @@ func randSeq(n int) string {
 
 func Init(baseUrl, username, password, clientId, apiVersion string) *Client {
 	client := &Client{
-		BaseURL:    baseUrl,
-		Username:   username,
-		Password:   password,
-		ClientID:   clientId,
-		APIVersion: apiVersion,
-		HttpClient: &http.Client{Timeout: 30 * time.Second},
+		BaseURL:      baseUrl,
+		Username:     username,
+		Password:     password,
+		ClientID:     clientId,
+		APIVersion:   apiVersion,
+		HttpClient:   &http.Client{Timeout: 30 * time.Second},
+		MaxRetries:   3,
+		RetryBackoff: 500 * time.Millisecond,
 	}
 	return client
 }
@@ -39,13 +41,21 @@ func (c *Client) authToken(password string) (string, string) {
 }
 
 func (c *Client) buildParams(extraParams map[string]string) url.Values {
-	salt := "randomsalt"
-	token, salt := c.authToken(c.Password)
+	c.mu.RLock()
+	apiVersion := c.APIVersion
+	c.mu.RUnlock()
+
 	params := url.Values{}
-	params.Add("u", c.Username)
-	params.Add("t", token)
-	params.Add("s", salt)
-	params.Add("v", c.APIVersion)
+	// apiKey 不能与 u/t/s 同时使用
+	if c.APIKey != "" && c.Supports(ExtAPIKeyAuth) {
+		params.Add("apiKey", c.APIKey)
+	} else {
+		token, salt := c.authToken(c.Password)
+		params.Add("u", c.Username)
+		params.Add("t", token)
+		params.Add("s", salt)
+	}
+	params.Add("v", apiVersion)
 	params.Add("c", c.ClientID)
 	params.Add("f", "json")
 
@@ -0,0 +1,44 @@
+package subsonic
+
+import (
+	"context"
+	"strconv"
+)
+
+func (c *Client) GetBookmarks() ([]Bookmark, error) {
+	return c.GetBookmarksContext(context.Background())
+}
+
+func (c *Client) GetBookmarksContext(ctx context.Context) ([]Bookmark, error) {
+	resp, err := c.request(ctx, "getBookmarks", nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Response.Bookmarks.Bookmarks, nil
+}
+
+// CreateBookmark 创建或更新书签，position 单位为毫秒
+func (c *Client) CreateBookmark(id string, position int64, comment string) error {
+	return c.CreateBookmarkContext(context.Background(), id, position, comment)
+}
+
+func (c *Client) CreateBookmarkContext(ctx context.Context, id string, position int64, comment string) error {
+	params := map[string]string{
+		"id":       id,
+		"position": strconv.FormatInt(position, 10),
+	}
+	if comment != "" {
+		params["comment"] = comment
+	}
+	_, err := c.request(ctx, "createBookmark", params)
+	return err
+}
+
+func (c *Client) DeleteBookmark(id string) error {
+	return c.DeleteBookmarkContext(context.Background(), id)
+}
+
+func (c *Client) DeleteBookmarkContext(ctx context.Context, id string) error {
+	_, err := c.request(ctx, "deleteBookmark", map[string]string{"id": id})
+	return err
+}
@@ -0,0 +1,152 @@
+package subsonic
+
+import (
+	"context"
+	"strconv"
+)
+
+type RandomSongsOptions struct {
+	Size     int
+	Genre    string
+	FromYear int
+	ToYear   int
+}
+
+func (c *Client) GetRandomSongs(opts RandomSongsOptions) ([]Song, error) {
+	return c.GetRandomSongsContext(context.Background(), opts)
+}
+
+func (c *Client) GetRandomSongsContext(ctx context.Context, opts RandomSongsOptions) ([]Song, error) {
+	params := map[string]string{
+		"size": strconv.Itoa(opts.Size),
+	}
+	if opts.Genre != "" {
+		params["genre"] = opts.Genre
+	}
+	if opts.FromYear > 0 {
+		params["fromYear"] = strconv.Itoa(opts.FromYear)
+	}
+	if opts.ToYear > 0 {
+		params["toYear"] = strconv.Itoa(opts.ToYear)
+	}
+
+	resp, err := c.request(ctx, "getRandomSongs", c.withMusicFolder(params))
+	if err != nil {
+		return nil, err
+	}
+	return resp.Response.RandomSongs.Songs, nil
+}
+
+// GetSimilarSongs 根据歌曲 ID 获取相似歌曲
+func (c *Client) GetSimilarSongs(songID string, count int) ([]Song, error) {
+	return c.GetSimilarSongsContext(context.Background(), songID, count)
+}
+
+func (c *Client) GetSimilarSongsContext(ctx context.Context, songID string, count int) ([]Song, error) {
+	resp, err := c.request(ctx, "getSimilarSongs", map[string]string{
+		"id":    songID,
+		"count": strconv.Itoa(count),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Response.SimilarSongs.Songs, nil
+}
+
+// GetSimilarSongs2 根据艺术家 ID 获取相似歌曲
+func (c *Client) GetSimilarSongs2(artistID string, count int) ([]Song, error) {
+	return c.GetSimilarSongs2Context(context.Background(), artistID, count)
+}
+
+func (c *Client) GetSimilarSongs2Context(ctx context.Context, artistID string, count int) ([]Song, error) {
+	resp, err := c.request(ctx, "getSimilarSongs2", map[string]string{
+		"id":    artistID,
+		"count": strconv.Itoa(count),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Response.SimilarSongs2.Songs, nil
+}
+
+// GetTopSongs 获取艺术家的热门歌曲
+func (c *Client) GetTopSongs(artist string, count int) ([]Song, error) {
+	return c.GetTopSongsContext(context.Background(), artist, count)
+}
+
+func (c *Client) GetTopSongsContext(ctx context.Context, artist string, count int) ([]Song, error) {
+	resp, err := c.request(ctx, "getTopSongs", map[string]string{
+		"artist": artist,
+		"count":  strconv.Itoa(count),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Response.TopSongs.Songs, nil
+}
+
+func (c *Client) GetGenres() ([]Genre, error) {
+	return c.GetGenresContext(context.Background())
+}
+
+func (c *Client) GetGenresContext(ctx context.Context) ([]Genre, error) {
+	resp, err := c.request(ctx, "getGenres", nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Response.Genres.Genres, nil
+}
+
+func (c *Client) GetMusicFolders() ([]MusicFolder, error) {
+	return c.GetMusicFoldersContext(context.Background())
+}
+
+func (c *Client) GetMusicFoldersContext(ctx context.Context) ([]MusicFolder, error) {
+	resp, err := c.request(ctx, "getMusicFolders", nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Response.MusicFolders.MusicFolders, nil
+}
+
+// GetAlbumList2 按 listType（random、newest、frequent 等）分页获取专辑列表
+func (c *Client) GetAlbumList2(listType string, size, offset int) ([]Album, error) {
+	return c.GetAlbumList2Context(context.Background(), listType, size, offset)
+}
+
+func (c *Client) GetAlbumList2Context(ctx context.Context, listType string, size, offset int) ([]Album, error) {
+	resp, err := c.request(ctx, "getAlbumList2", c.withMusicFolder(map[string]string{
+		"type":   listType,
+		"size":   strconv.Itoa(size),
+		"offset": strconv.Itoa(offset),
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return resp.Response.AlbumList2.Albums, nil
+}
+
+// GetAlbum 获取专辑及其中的歌曲
+func (c *Client) GetAlbum(id string) (Album, error) {
+	return c.GetAlbumContext(context.Background(), id)
+}
+
+func (c *Client) GetAlbumContext(ctx context.Context, id string) (Album, error) {
+	resp, err := c.request(ctx, "getAlbum", map[string]string{"id": id})
+	if err != nil {
+		return Album{}, err
+	}
+	return resp.Response.Album, nil
+}
+
+func (c *Client) GetStarredSongs() ([]Song, error) {
+	return c.GetStarredSongsContext(context.Background())
+}
+
+func (c *Client) GetStarredSongsContext(ctx context.Context) ([]Song, error) {
+	resp, err := c.request(ctx, "getStarred2", c.withMusicFolder(nil))
+	if err != nil {
+		return nil, err
+	}
+	return resp.Response.Starred2.Songs, nil
+}
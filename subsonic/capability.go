@@ -0,0 +1,105 @@
+package subsonic
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// 客户端支持的最高 API 版本，服务器版本更低时以服务器为准
+const DefaultAPIVersion = "1.16.1"
+
+// OpenSubsonic 扩展名称
+const (
+	ExtFormPost        = "formPost"
+	ExtSongLyrics      = "songLyrics"
+	ExtTranscodeOffset = "transcodeOffset"
+	ExtAPIKeyAuth      = "apiKeyAuthentication"
+)
+
+type Extension struct {
+	Name     string `json:"name"`
+	Versions []int  `json:"versions"`
+}
+
+// ServerInfo ping 返回的服务器信息
+type ServerInfo struct {
+	Type          string
+	ServerVersion string
+	APIVersion    string
+	OpenSubsonic  bool
+	Extensions    []Extension
+}
+
+// Negotiate 调用 ping 和 getOpenSubsonicExtensions 检测服务器能力，
+// 并按服务器支持的 API 版本调整请求中的 v 参数
+func (c *Client) Negotiate(ctx context.Context) (*ServerInfo, error) {
+	resp, err := c.request(ctx, "ping", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &ServerInfo{
+		Type:          resp.Response.Type,
+		ServerVersion: resp.Response.ServerVersion,
+		APIVersion:    resp.Response.Version,
+		OpenSubsonic:  resp.Response.OpenSubsonic,
+	}
+
+	if info.OpenSubsonic {
+		extResp, err := c.request(ctx, "getOpenSubsonicExtensions", nil)
+		if err != nil {
+			return nil, err
+		}
+		info.Extensions = extResp.Response.OpenSubsonicExtensions
+	}
+
+	c.mu.Lock()
+	c.server = info
+	c.extensions = make(map[string][]int, len(info.Extensions))
+	for _, ext := range info.Extensions {
+		c.extensions[ext.Name] = ext.Versions
+	}
+	if info.APIVersion != "" && compareVersion(info.APIVersion, c.APIVersion) < 0 {
+		c.APIVersion = info.APIVersion
+	}
+	c.mu.Unlock()
+
+	return info, nil
+}
+
+// ServerInfo 返回 Negotiate 检测到的服务器信息，未检测时返回 nil
+func (c *Client) ServerInfo() *ServerInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.server
+}
+
+// Supports 判断服务器是否支持指定的 OpenSubsonic 扩展
+func (c *Client) Supports(extension string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, ok := c.extensions[extension]
+	return ok
+}
+
+// compareVersion 比较形如 1.16.1 的版本号
+func compareVersion(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < max(len(as), len(bs)); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
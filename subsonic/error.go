@@ -0,0 +1,88 @@
+package subsonic
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+)
+
+// Subsonic API 文档中定义的错误码
+const (
+	ErrorGeneric                   = 0
+	ErrorMissingParameter          = 10
+	ErrorClientTooOld              = 20
+	ErrorServerTooOld              = 30
+	ErrorWrongCredentials          = 40
+	ErrorTokenAuthNotSupported     = 41
+	ErrorAuthMechanismNotSupported = 42
+	ErrorConflictingAuth           = 43
+	ErrorInvalidAPIKey             = 44
+	ErrorNotAuthorized             = 50
+	ErrorTrialExpired              = 60
+	ErrorNotFound                  = 70
+)
+
+// 可用于 errors.Is 判断的错误
+var (
+	ErrMissingParameter = &Error{Code: ErrorMissingParameter}
+	ErrClientTooOld     = &Error{Code: ErrorClientTooOld}
+	ErrServerTooOld     = &Error{Code: ErrorServerTooOld}
+	ErrWrongCredentials = &Error{Code: ErrorWrongCredentials}
+	ErrNotAuthorized    = &Error{Code: ErrorNotAuthorized}
+	ErrNotFound         = &Error{Code: ErrorNotFound}
+)
+
+// Error 服务器返回 status=failed 时的错误
+type Error struct {
+	Code    int
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("subsonic error %d", e.Code)
+	}
+	return fmt.Sprintf("subsonic error %d: %s", e.Code, e.Message)
+}
+
+// Is 按错误码比较，使 errors.Is(err, ErrNotFound) 可用
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// StatusError 服务器返回非 200 状态码
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status: %d, response: %s", e.StatusCode, e.Body)
+}
+
+// isTransient 判断错误是否为可重试的临时错误：超时、连接错误、429 和 5xx
+func isTransient(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == 429 || statusErr.StatusCode >= 500
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// isNotSent 判断请求是否确定没有被服务器执行：连接未建立，或被 429 限流拒绝。
+// 此时重发写操作不会产生重复
+func isNotSent(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == 429
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
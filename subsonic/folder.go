@@ -0,0 +1,27 @@
+package subsonic
+
+// SetMusicFolder 设置列表类接口使用的音乐库，空字符串表示全部音乐库
+func (c *Client) SetMusicFolder(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.musicFolderID = id
+}
+
+func (c *Client) MusicFolder() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.musicFolderID
+}
+
+// withMusicFolder 为列表类接口附加 musicFolderId 参数
+func (c *Client) withMusicFolder(params map[string]string) map[string]string {
+	id := c.MusicFolder()
+	if id == "" {
+		return params
+	}
+	if params == nil {
+		params = make(map[string]string)
+	}
+	params["musicFolderId"] = id
+	return params
+}
@@ -0,0 +1,67 @@
+package subsonic
+
+import (
+	"context"
+	"strings"
+)
+
+type LyricLine struct {
+	Start int64  `json:"start"` // 毫秒，仅同步歌词有
+	Value string `json:"value"`
+}
+
+type StructuredLyrics struct {
+	Lang          string      `json:"lang"`
+	Synced        bool        `json:"synced"`
+	Offset        int64       `json:"offset"`
+	DisplayArtist string      `json:"displayArtist"`
+	DisplayTitle  string      `json:"displayTitle"`
+	Lines         []LyricLine `json:"line"`
+}
+
+func (c *Client) GetLyrics(song Song) (*StructuredLyrics, error) {
+	return c.GetLyricsContext(context.Background(), song)
+}
+
+// GetLyricsContext 服务器支持 songLyrics 扩展时获取结构化（可能带时间轴）歌词，
+// 否则退回到只按艺术家和标题匹配的 getLyrics 纯文本歌词
+func (c *Client) GetLyricsContext(ctx context.Context, song Song) (*StructuredLyrics, error) {
+	if c.Supports(ExtSongLyrics) {
+		resp, err := c.request(ctx, "getLyricsBySongId", map[string]string{"id": song.ID})
+		if err != nil {
+			return nil, err
+		}
+		list := resp.Response.LyricsList.StructuredLyrics
+		if len(list) == 0 {
+			return nil, &Error{Code: ErrorNotFound, Message: "lyrics not found"}
+		}
+		// 优先使用同步歌词
+		for i := range list {
+			if list[i].Synced {
+				return &list[i], nil
+			}
+		}
+		return &list[0], nil
+	}
+
+	resp, err := c.request(ctx, "getLyrics", map[string]string{
+		"artist": song.Artist,
+		"title":  song.Title,
+	})
+	if err != nil {
+		return nil, err
+	}
+	value := strings.TrimSpace(resp.Response.Lyrics.Value)
+	if value == "" {
+		return nil, &Error{Code: ErrorNotFound, Message: "lyrics not found"}
+	}
+
+	lyrics := &StructuredLyrics{
+		DisplayArtist: resp.Response.Lyrics.Artist,
+		DisplayTitle:  resp.Response.Lyrics.Title,
+	}
+	for _, line := range strings.Split(value, "\n") {
+		lyrics.Lines = append(lyrics.Lines, LyricLine{Value: strings.TrimRight(line, "\r")})
+	}
+	return lyrics, nil
+}
@@ -2,6 +2,7 @@ package subsonic
 
 import (
 	"net/http"
+	"sync"
 	"time"
 )
 
@@ -12,6 +13,17 @@ type Client struct {
 	ClientID   string
 	APIVersion string
 	HttpClient *http.Client
+	// 设置后且服务器支持 apiKeyAuthentication 扩展时使用 API key 认证
+	APIKey string
+
+	// 临时错误的最大重试次数及首次重试等待时间
+	MaxRetries   int
+	RetryBackoff time.Duration
+
+	mu            sync.RWMutex
+	musicFolderID string
+	server        *ServerInfo
+	extensions    map[string][]int
 }
 
 type SubsonicResponse struct {
@@ -24,6 +36,59 @@ type SubsonicResponse struct {
 		RandomSongs   struct {
 			Songs []Song `json:"song"`
 		} `json:"randomSongs"`
+		SimilarSongs struct {
+			Songs []Song `json:"song"`
+		} `json:"similarSongs"`
+		SimilarSongs2 struct {
+			Songs []Song `json:"song"`
+		} `json:"similarSongs2"`
+		TopSongs struct {
+			Songs []Song `json:"song"`
+		} `json:"topSongs"`
+		Genres struct {
+			Genres []Genre `json:"genre"`
+		} `json:"genres"`
+		MusicFolders struct {
+			MusicFolders []MusicFolder `json:"musicFolder"`
+		} `json:"musicFolders"`
+		AlbumList2 struct {
+			Albums []Album `json:"album"`
+		} `json:"albumList2"`
+		Album    Album `json:"album"`
+		Starred2 struct {
+			Songs []Song `json:"song"`
+		} `json:"starred2"`
+		InternetRadioStations struct {
+			Stations []InternetRadioStation `json:"internetRadioStation"`
+		} `json:"internetRadioStations"`
+		Podcasts struct {
+			Channels []PodcastChannel `json:"channel"`
+		} `json:"podcasts"`
+		NewestPodcasts struct {
+			Episodes []PodcastEpisode `json:"episode"`
+		} `json:"newestPodcasts"`
+		Bookmarks struct {
+			Bookmarks []Bookmark `json:"bookmark"`
+		} `json:"bookmarks"`
+		SearchResult3 struct {
+			Songs []Song `json:"song"`
+		} `json:"searchResult3"`
+		Playlists struct {
+			Playlists []Playlist `json:"playlist"`
+		} `json:"playlists"`
+		Playlist Playlist `json:"playlist"`
+		Shares   struct {
+			Shares []Share `json:"share"`
+		} `json:"shares"`
+		OpenSubsonicExtensions []Extension `json:"openSubsonicExtensions"`
+		LyricsList             struct {
+			StructuredLyrics []StructuredLyrics `json:"structuredLyrics"`
+		} `json:"lyricsList"`
+		Lyrics struct {
+			Artist string `json:"artist"`
+			Title  string `json:"title"`
+			Value  string `json:"value"`
+		} `json:"lyrics"`
 		Error struct {
 			Code    int    `json:"code"`
 			Message string `json:"message"`
@@ -38,6 +103,8 @@ type Song struct {
 	Artist       string    `json:"artist"`
 	Duration     int       `json:"duration"` // 秒数
 	Track        int       `json:"track"`
+	Year         int       `json:"year"`
+	Genre        string    `json:"genre"`
 	CoverArt     string    `json:"coverArt"`
 	Size         int64     `json:"size"`
 	ContentType  string    `json:"contentType"`
@@ -45,6 +112,8 @@ type Song struct {
 	BitRate      int       `json:"bitRate"`
 	Path         string    `json:"path"`
 	PlayCount    int       `json:"playCount"`
+	UserRating   int       `json:"userRating"`
+	Starred      time.Time `json:"starred,omitempty"`
 	Created      time.Time `json:"created"`
 	AlbumID      string    `json:"albumId"`
 	ArtistID     string    `json:"artistId"`
@@ -52,4 +121,91 @@ type Song struct {
 	Played       time.Time `json:"played,omitempty"`
 	ChannelCount int       `json:"channelCount"`
 	SampleRate   int       `json:"samplingRate"`
+	Type         string    `json:"type"` // music、podcast、audiobook
+}
+
+type Genre struct {
+	Value      string `json:"value"`
+	SongCount  int    `json:"songCount"`
+	AlbumCount int    `json:"albumCount"`
+}
+
+type MusicFolder struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type Album struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Artist    string    `json:"artist"`
+	ArtistID  string    `json:"artistId"`
+	CoverArt  string    `json:"coverArt"`
+	SongCount int       `json:"songCount"`
+	Duration  int       `json:"duration"`
+	Year      int       `json:"year"`
+	Genre     string    `json:"genre"`
+	Created   time.Time `json:"created"`
+	Songs     []Song    `json:"song,omitempty"` // 仅 getAlbum 返回
+}
+
+type Playlist struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Comment   string    `json:"comment"`
+	Owner     string    `json:"owner"`
+	Public    bool      `json:"public"`
+	SongCount int       `json:"songCount"`
+	Duration  int       `json:"duration"`
+	Changed   time.Time `json:"changed"`
+	Songs     []Song    `json:"entry,omitempty"` // 仅 getPlaylist 返回
+}
+
+type Share struct {
+	ID          string    `json:"id"`
+	URL         string    `json:"url"`
+	Description string    `json:"description"`
+	Created     time.Time `json:"created"`
+	Expires     time.Time `json:"expires,omitempty"`
+	Entries     []Song    `json:"entry"`
+}
+
+type InternetRadioStation struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	StreamURL   string `json:"streamUrl"`
+	HomePageURL string `json:"homePageUrl"`
+}
+
+type PodcastChannel struct {
+	ID          string           `json:"id"`
+	URL         string           `json:"url"`
+	Title       string           `json:"title"`
+	Description string           `json:"description"`
+	CoverArt    string           `json:"coverArt"`
+	Status      string           `json:"status"`
+	Episodes    []PodcastEpisode `json:"episode"`
+}
+
+type PodcastEpisode struct {
+	ID          string    `json:"id"`
+	StreamID    string    `json:"streamId"` // 已下载到服务器后才有，用于播放
+	ChannelID   string    `json:"channelId"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	PublishDate time.Time `json:"publishDate"`
+	Status      string    `json:"status"` // new、downloading、completed、error、deleted、skipped
+	Duration    int       `json:"duration"`
+	Size        int64     `json:"size"`
+	Suffix      string    `json:"suffix"`
+	ContentType string    `json:"contentType"`
+}
+
+type Bookmark struct {
+	Position int64     `json:"position"` // 毫秒
+	Username string    `json:"username"`
+	Comment  string    `json:"comment"`
+	Created  time.Time `json:"created"`
+	Changed  time.Time `json:"changed"`
+	Entry    Song      `json:"entry"`
 }
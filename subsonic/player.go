@@ -1,110 +1,72 @@
 package subsonic
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
-	"net/http"
+	"strconv"
 )
 
 func (c *Client) GetPlaylists() ([]Song, error) {
-	params := c.buildParams(map[string]string{
-		"size":   "500",
-		"format": "json",
-	})
-	requestUrl := fmt.Sprintf("%s/rest/getRandomSongs?%s", c.BaseURL, params.Encode())
-
-	req, err := http.NewRequest("GET", requestUrl, nil)
-	if err != nil {
-		return nil, fmt.Errorf("创建请求失败: %w", err)
-	}
-
-	req.Header.Set("Accept", "application/json")
-	resp, err := c.HttpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("请求失败: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status: %d, response: %s", resp.StatusCode, string(body))
-	}
+	return c.GetPlaylistsContext(context.Background())
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("read response failed: %w", err)
-	}
+func (c *Client) GetPlaylistsContext(ctx context.Context) ([]Song, error) {
+	return c.GetRandomSongsContext(ctx, RandomSongsOptions{Size: 500})
+}
 
-	var subsonicResp struct {
-		SubsonicResponse struct {
-			Status string `json:"status"`
-			Error  struct {
-				Code    int    `json:"code"`
-				Message string `json:"message"`
-			} `json:"error"`
-			RandomSongs struct {
-				Songs []Song `json:"song"`
-			} `json:"randomSongs"`
-		} `json:"subsonic-response"`
-	}
+// GetServerInfo 调用 ping 检查服务器连接和认证信息
+func (c *Client) GetServerInfo() error {
+	return c.GetServerInfoContext(context.Background())
+}
 
-	if err := json.Unmarshal(body, &subsonicResp); err != nil {
-		return nil, fmt.Errorf("JSON解析失败: %w", err)
-	}
+func (c *Client) GetServerInfoContext(ctx context.Context) error {
+	_, err := c.request(ctx, "ping", nil)
+	return err
+}
 
-	if subsonicResp.SubsonicResponse.Status != "ok" {
-		return nil, fmt.Errorf("subsonic错误 %d: %s",
-			subsonicResp.SubsonicResponse.Error.Code,
-			subsonicResp.SubsonicResponse.Error.Message)
-	}
+func (c *Client) SearchSongs(query string) ([]Song, error) {
+	return c.SearchSongsContext(context.Background(), query)
+}
 
-	return subsonicResp.SubsonicResponse.RandomSongs.Songs, nil
+func (c *Client) SearchSongsContext(ctx context.Context, query string) ([]Song, error) {
+	return c.SearchSongsPageContext(ctx, query, 10, 0)
 }
 
-func (c *Client) GetServerInfo() error {
-	params := c.buildParams(map[string]string{})
-	requestUrl := fmt.Sprintf("%s/rest/ping?%s", c.BaseURL, params.Encode())
-	resp, err := http.Get(requestUrl)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	fmt.Println(resp)
-	return nil
+// SearchSongsPage 分页搜索歌曲，offset 为已获取的结果数
+func (c *Client) SearchSongsPage(query string, count, offset int) ([]Song, error) {
+	return c.SearchSongsPageContext(context.Background(), query, count, offset)
 }
-func (c *Client) SearchSongs(query string) ([]Song, error) {
-	params := c.buildParams(map[string]string{
-		"query":     query,
-		"songCount": "10",
-	})
 
-	resp, err := http.Get(fmt.Sprintf("%s/rest/search3.view?%s", c.BaseURL, params.Encode()))
+func (c *Client) SearchSongsPageContext(ctx context.Context, query string, count, offset int) ([]Song, error) {
+	resp, err := c.request(ctx, "search3", c.withMusicFolder(map[string]string{
+		"query":       query,
+		"songCount":   strconv.Itoa(count),
+		"songOffset":  strconv.Itoa(offset),
+		"artistCount": "0",
+		"albumCount":  "0",
+	}))
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	var result struct {
-		SubsonicResponse struct {
-			SearchResult3 struct {
-				Songs []Song `json:"song"`
-			} `json:"searchResult3"`
-		} `json:"subsonic-response"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		fmt.Println(err)
-
-		return nil, err
-	}
-
-	return result.SubsonicResponse.SearchResult3.Songs, nil
+	return resp.Response.SearchResult3.Songs, nil
 }
 
 func (c *Client) GetPlayURL(songID string) string {
-	params := c.buildParams(map[string]string{
+	playURL, _ := c.GetPlayURLAt(songID, 0)
+	return playURL
+}
+
+// GetPlayURLAt 返回从 offset 秒开始播放的地址。服务器支持 transcodeOffset 扩展时
+// 由服务器从该位置开始转码并返回 true，否则返回 false，需要播放器自行定位
+func (c *Client) GetPlayURLAt(songID string, offset int) (string, bool) {
+	extra := map[string]string{
 		"id":     songID,
 		"format": "mp3",
-	})
-	return fmt.Sprintf("%s/rest/stream.view?%s", c.BaseURL, params.Encode())
+	}
+	serverSeek := offset > 0 && c.Supports(ExtTranscodeOffset)
+	if serverSeek {
+		extra["timeOffset"] = strconv.Itoa(offset)
+	}
+	params := c.buildParams(extra)
+	return fmt.Sprintf("%s/rest/stream.view?%s", c.BaseURL, params.Encode()), serverSeek
 }
@@ -0,0 +1,54 @@
+package subsonic
+
+import (
+	"context"
+	"net/url"
+)
+
+// ListPlaylists 获取当前用户可见的播放列表
+func (c *Client) ListPlaylists() ([]Playlist, error) {
+	return c.ListPlaylistsContext(context.Background())
+}
+
+func (c *Client) ListPlaylistsContext(ctx context.Context) ([]Playlist, error) {
+	resp, err := c.request(ctx, "getPlaylists", nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Response.Playlists.Playlists, nil
+}
+
+// GetPlaylist 获取播放列表及其中的歌曲
+func (c *Client) GetPlaylist(id string) (Playlist, error) {
+	return c.GetPlaylistContext(context.Background(), id)
+}
+
+func (c *Client) GetPlaylistContext(ctx context.Context, id string) (Playlist, error) {
+	resp, err := c.request(ctx, "getPlaylist", map[string]string{"id": id})
+	if err != nil {
+		return Playlist{}, err
+	}
+	return resp.Response.Playlist, nil
+}
+
+// CreatePlaylist 创建包含 songIDs 的播放列表
+func (c *Client) CreatePlaylist(name string, songIDs []string) error {
+	return c.CreatePlaylistContext(context.Background(), name, songIDs)
+}
+
+func (c *Client) CreatePlaylistContext(ctx context.Context, name string, songIDs []string) error {
+	params := url.Values{"name": {name}, "songId": songIDs}
+	_, err := c.requestValues(ctx, "createPlaylist", params)
+	return err
+}
+
+// AddToPlaylist 向播放列表末尾追加歌曲
+func (c *Client) AddToPlaylist(playlistID string, songIDs []string) error {
+	return c.AddToPlaylistContext(context.Background(), playlistID, songIDs)
+}
+
+func (c *Client) AddToPlaylistContext(ctx context.Context, playlistID string, songIDs []string) error {
+	params := url.Values{"playlistId": {playlistID}, "songIdToAdd": songIDs}
+	_, err := c.requestValues(ctx, "updatePlaylist", params)
+	return err
+}
@@ -0,0 +1,92 @@
+package subsonic
+
+import (
+	"context"
+	"strconv"
+)
+
+// GetPodcasts 获取播客频道，includeEpisodes 为 false 时只返回频道信息
+func (c *Client) GetPodcasts(includeEpisodes bool) ([]PodcastChannel, error) {
+	return c.GetPodcastsContext(context.Background(), includeEpisodes)
+}
+
+func (c *Client) GetPodcastsContext(ctx context.Context, includeEpisodes bool) ([]PodcastChannel, error) {
+	resp, err := c.request(ctx, "getPodcasts", map[string]string{
+		"includeEpisodes": strconv.FormatBool(includeEpisodes),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Response.Podcasts.Channels, nil
+}
+
+// GetPodcastChannel 获取单个频道及其全部节目
+func (c *Client) GetPodcastChannel(id string) (*PodcastChannel, error) {
+	return c.GetPodcastChannelContext(context.Background(), id)
+}
+
+func (c *Client) GetPodcastChannelContext(ctx context.Context, id string) (*PodcastChannel, error) {
+	resp, err := c.request(ctx, "getPodcasts", map[string]string{
+		"id":              id,
+		"includeEpisodes": "true",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Response.Podcasts.Channels) == 0 {
+		return nil, &Error{Code: ErrorNotFound, Message: "podcast channel not found: " + id}
+	}
+	return &resp.Response.Podcasts.Channels[0], nil
+}
+
+func (c *Client) GetNewestPodcasts(count int) ([]PodcastEpisode, error) {
+	return c.GetNewestPodcastsContext(context.Background(), count)
+}
+
+func (c *Client) GetNewestPodcastsContext(ctx context.Context, count int) ([]PodcastEpisode, error) {
+	resp, err := c.request(ctx, "getNewestPodcasts", map[string]string{
+		"count": strconv.Itoa(count),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Response.NewestPodcasts.Episodes, nil
+}
+
+// RefreshPodcasts 让服务器检查所有频道的新节目
+func (c *Client) RefreshPodcasts() error {
+	return c.RefreshPodcastsContext(context.Background())
+}
+
+func (c *Client) RefreshPodcastsContext(ctx context.Context) error {
+	_, err := c.request(ctx, "refreshPodcasts", nil)
+	return err
+}
+
+func (c *Client) CreatePodcastChannel(url string) error {
+	return c.CreatePodcastChannelContext(context.Background(), url)
+}
+
+func (c *Client) CreatePodcastChannelContext(ctx context.Context, url string) error {
+	_, err := c.request(ctx, "createPodcastChannel", map[string]string{"url": url})
+	return err
+}
+
+// DownloadPodcastEpisode 让服务器下载节目，下载完成后才能播放
+func (c *Client) DownloadPodcastEpisode(id string) error {
+	return c.DownloadPodcastEpisodeContext(context.Background(), id)
+}
+
+func (c *Client) DownloadPodcastEpisodeContext(ctx context.Context, id string) error {
+	_, err := c.request(ctx, "downloadPodcastEpisode", map[string]string{"id": id})
+	return err
+}
+
+func (c *Client) DeletePodcastEpisode(id string) error {
+	return c.DeletePodcastEpisodeContext(context.Background(), id)
+}
+
+func (c *Client) DeletePodcastEpisodeContext(ctx context.Context, id string) error {
+	_, err := c.request(ctx, "deletePodcastEpisode", map[string]string{"id": id})
+	return err
+}
@@ -0,0 +1,57 @@
+package subsonic
+
+import (
+	"context"
+)
+
+func (c *Client) GetInternetRadioStations() ([]InternetRadioStation, error) {
+	return c.GetInternetRadioStationsContext(context.Background())
+}
+
+func (c *Client) GetInternetRadioStationsContext(ctx context.Context) ([]InternetRadioStation, error) {
+	resp, err := c.request(ctx, "getInternetRadioStations", nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Response.InternetRadioStations.Stations, nil
+}
+
+func (c *Client) CreateInternetRadioStation(streamURL, name, homePageURL string) error {
+	return c.CreateInternetRadioStationContext(context.Background(), streamURL, name, homePageURL)
+}
+
+func (c *Client) CreateInternetRadioStationContext(ctx context.Context, streamURL, name, homePageURL string) error {
+	_, err := c.request(ctx, "createInternetRadioStation", stationParams(streamURL, name, homePageURL))
+	return err
+}
+
+func (c *Client) UpdateInternetRadioStation(id, streamURL, name, homePageURL string) error {
+	return c.UpdateInternetRadioStationContext(context.Background(), id, streamURL, name, homePageURL)
+}
+
+func (c *Client) UpdateInternetRadioStationContext(ctx context.Context, id, streamURL, name, homePageURL string) error {
+	params := stationParams(streamURL, name, homePageURL)
+	params["id"] = id
+	_, err := c.request(ctx, "updateInternetRadioStation", params)
+	return err
+}
+
+func (c *Client) DeleteInternetRadioStation(id string) error {
+	return c.DeleteInternetRadioStationContext(context.Background(), id)
+}
+
+func (c *Client) DeleteInternetRadioStationContext(ctx context.Context, id string) error {
+	_, err := c.request(ctx, "deleteInternetRadioStation", map[string]string{"id": id})
+	return err
+}
+
+func stationParams(streamURL, name, homePageURL string) map[string]string {
+	params := map[string]string{
+		"streamUrl": streamURL,
+		"name":      name,
+	}
+	if homePageURL != "" {
+		params["homePageUrl"] = homePageURL
+	}
+	return params
+}
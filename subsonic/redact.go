@@ -0,0 +1,36 @@
+package subsonic
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// 认证相关的请求参数
+var authParams = []string{"u", "p", "t", "s", "apiKey"}
+
+// RedactURL 隐藏 URL 中的认证参数，用于日志和错误信息
+func RedactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "<invalid url>"
+	}
+	query := u.Query()
+	for _, key := range authParams {
+		if query.Has(key) {
+			query.Set(key, "REDACTED")
+		}
+	}
+	u.RawQuery = query.Encode()
+	u.User = nil
+	return u.String()
+}
+
+// RedactError 隐藏 *url.Error 中带认证参数的请求地址
+func RedactError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) && strings.Contains(urlErr.URL, "?") {
+		urlErr.URL = RedactURL(urlErr.URL)
+	}
+	return err
+}
@@ -0,0 +1,131 @@
+package subsonic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// writeEndpoints 会修改服务器状态的接口。请求可能已被服务器执行后才超时或断开，
+// 重发会创建重复的歌单、分享、频道或重复添加歌曲，因此只在请求确定未送达时重试
+var writeEndpoints = map[string]bool{
+	"star":                       true,
+	"unstar":                     true,
+	"createShare":                true,
+	"createPlaylist":             true,
+	"updatePlaylist":             true,
+	"createBookmark":             true,
+	"deleteBookmark":             true,
+	"refreshPodcasts":            true,
+	"createPodcastChannel":       true,
+	"downloadPodcastEpisode":     true,
+	"deletePodcastEpisode":       true,
+	"createInternetRadioStation": true,
+	"updateInternetRadioStation": true,
+	"deleteInternetRadioStation": true,
+}
+
+// request 所有接口共用的请求流程：临时错误按指数退避重试（写接口仅在请求未送达时重试），
+// ctx 取消时立即返回，服务器错误转换为 *Error
+func (c *Client) request(ctx context.Context, endpoint string, extraParams map[string]string) (*SubsonicResponse, error) {
+	values := url.Values{}
+	for k, v := range extraParams {
+		values.Set(k, v)
+	}
+	return c.requestValues(ctx, endpoint, values)
+}
+
+// requestValues 与 request 相同，用于同一参数需要出现多次的接口（如多个 id）
+func (c *Client) requestValues(ctx context.Context, endpoint string, extraParams url.Values) (*SubsonicResponse, error) {
+	var lastErr error
+	for attempt := 0; attempt <= c.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(c.backoff(attempt)):
+			}
+		}
+
+		resp, err := c.do(ctx, endpoint, extraParams)
+		if err == nil {
+			return resp, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if !isTransient(err) || (writeEndpoints[endpoint] && !isNotSent(err)) {
+			return nil, err
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("%s failed after %d retries: %w", endpoint, c.MaxRetries, lastErr)
+}
+
+// newRequest 服务器支持 formPost 扩展时以 POST 表单提交参数，
+// 避免认证信息出现在 URL 和服务器访问日志中
+func (c *Client) newRequest(ctx context.Context, endpoint string, extraParams url.Values) (*http.Request, error) {
+	params := c.buildParams(nil)
+	for k, values := range extraParams {
+		params[k] = append(params[k], values...)
+	}
+	requestUrl := fmt.Sprintf("%s/rest/%s", c.BaseURL, endpoint)
+
+	if c.Supports(ExtFormPost) {
+		req, err := http.NewRequestWithContext(ctx, "POST", requestUrl, strings.NewReader(params.Encode()))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req, nil
+	}
+	return http.NewRequestWithContext(ctx, "GET", requestUrl+"?"+params.Encode(), nil)
+}
+
+// backoff 第 attempt 次重试前的等待时间，带随机抖动
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.RetryBackoff << (attempt - 1)
+	return delay + time.Duration(rand.Int63n(int64(delay)/2+1))
+}
+
+func (c *Client) do(ctx context.Context, endpoint string, extraParams url.Values) (*SubsonicResponse, error) {
+	req, err := c.newRequest(ctx, endpoint, extraParams)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", RedactError(err))
+	}
+
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.HttpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求失败: %w", RedactError(err))
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response failed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	var subsonicResp SubsonicResponse
+	if err := json.Unmarshal(body, &subsonicResp); err != nil {
+		return nil, fmt.Errorf("JSON解析失败: %w", err)
+	}
+
+	if subsonicResp.Response.Status != "ok" {
+		return nil, &Error{
+			Code:    subsonicResp.Response.Error.Code,
+			Message: subsonicResp.Response.Error.Message,
+		}
+	}
+	return &subsonicResp, nil
+}
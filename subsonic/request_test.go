@@ -0,0 +1,118 @@
+package subsonic
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testClient(baseURL string) *Client {
+	c := Init(baseURL, "user", "pass", "test", "1.16.1")
+	c.RetryBackoff = time.Millisecond
+	return c
+}
+
+func TestRequestRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint string
+		status   int
+		want     int32
+	}{
+		{"read retries 5xx", "getGenres", http.StatusServiceUnavailable, 4},
+		{"write does not retry 5xx", "createPlaylist", http.StatusServiceUnavailable, 1},
+		{"write retries 429", "createShare", http.StatusTooManyRequests, 4},
+		{"read does not retry 404", "getAlbum", http.StatusNotFound, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			if _, err := testClient(srv.URL).request(context.Background(), tt.endpoint, nil); err == nil {
+				t.Fatal("expected error")
+			}
+			if got := calls.Load(); got != tt.want {
+				t.Errorf("calls = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestWriteNotRetriedAfterSend(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		// 服务器已收到请求后断开连接，写操作可能已生效
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		conn.Close()
+	}))
+	defer srv.Close()
+
+	if _, err := testClient(srv.URL).request(context.Background(), "createPodcastChannel", nil); err == nil {
+		t.Fatal("expected error")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+}
+
+func TestIsNotSent(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	_, err = testClient("http://"+addr).do(context.Background(), "star", nil)
+	if err == nil || !isNotSent(err) {
+		t.Errorf("dial failure: isNotSent(%v) = false, want true", err)
+	}
+	if isNotSent(&StatusError{StatusCode: 502}) {
+		t.Error("502 should not count as not sent")
+	}
+	if !strings.Contains(err.Error(), "请求失败") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRequestFailedStatus(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"subsonic-response":{"status":"failed","version":"1.16.1",` +
+			`"error":{"code":70,"message":"Bookmark not found"}}}`))
+	}))
+	defer srv.Close()
+
+	err := testClient(srv.URL).DeleteBookmarkContext(context.Background(), "song-1")
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("error %v is not a *Error", err)
+	}
+	if apiErr.Code != ErrorNotFound || apiErr.Message != "Bookmark not found" {
+		t.Errorf("error = %+v, want code 70 with message", apiErr)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("errors.Is(err, ErrNotFound) = false")
+	}
+	if errors.Is(err, ErrNotAuthorized) {
+		t.Error("errors.Is(err, ErrNotAuthorized) = true")
+	}
+	// 服务器返回的错误不是临时错误，不重试
+	if got := calls.Load(); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+}
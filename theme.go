@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// colorTheme 界面配色，text 和 secondary 分别替代表格中的白色和灰色文字
+type colorTheme struct {
+	background tcell.Color
+	contrast   tcell.Color
+	border     tcell.Color
+	text       tcell.Color
+	secondary  tcell.Color
+}
+
+var themes = map[string]colorTheme{
+	"dark": {
+		background: tcell.ColorBlack,
+		contrast:   tcell.ColorDarkBlue,
+		border:     tcell.ColorWhite,
+		text:       tcell.ColorWhite,
+		secondary:  tcell.ColorGray,
+	},
+	"light": {
+		background: tcell.ColorWhite,
+		contrast:   tcell.ColorLightBlue,
+		border:     tcell.ColorDarkSlateGray,
+		text:       tcell.ColorBlack,
+		secondary:  tcell.ColorDimGray,
+	},
+}
+
+func themeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyTheme 设置 tview 的默认样式，之后创建的界面元素使用新配色
+func (a *Application) applyTheme(name string) error {
+	t, ok := themes[name]
+	if !ok {
+		return fmt.Errorf("unknown theme: %s", name)
+	}
+	a.theme = name
+
+	tview.Styles.PrimitiveBackgroundColor = t.background
+	tview.Styles.ContrastBackgroundColor = t.contrast
+	tview.Styles.BorderColor = t.border
+	tview.Styles.TitleColor = t.text
+	tview.Styles.GraphicsColor = t.border
+	tview.Styles.PrimaryTextColor = t.text
+	return nil
+}
+
+// themeColor 将表格中的白色和灰色文字换成当前配色
+func (a *Application) themeColor(color tcell.Color) tcell.Color {
+	t, ok := themes[a.theme]
+	if !ok {
+		return color
+	}
+	switch color {
+	case tcell.ColorWhite:
+		return t.text
+	case tcell.ColorGray:
+		return t.secondary
+	}
+	return color
+}
+
+// themeCommand 切换配色并写回配置 ui.theme，重新创建主界面使配色生效
+func (a *Application) themeCommand(name string) error {
+	if name == "" {
+		a.setMessage("[lightgreen]themes: " + strings.Join(themeNames(), ", "))
+		return nil
+	}
+	if err := a.applyTheme(name); err != nil {
+		return err
+	}
+
+	if err := saveSettings(map[string]any{"ui.theme": name}); err != nil {
+		log.Println("save theme failed:", err)
+	}
+
+	a.createHomepage()
+	a.renderSongTable()
+	if a.currentSongIndex >= 0 {
+		a.showSong(a.currentSongIndex)
+	}
+	return nil
+}